@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alexflint/go-arg"
+	"github.com/studio-b12/goat/pkg/clr"
+	"github.com/studio-b12/goat/pkg/executor"
+	"github.com/zekrotja/rogu/log"
+)
+
+type LoadArgs struct {
+	Goatfile string `arg:"positional,required" help:"Goatfile location"`
+
+	CommonArgs
+
+	VirtualUsers int           `arg:"-u,--vus,env:GOATARG_VUS" default:"1" help:"Number of virtual users executing iterations concurrently"`
+	Duration     time.Duration `arg:"--duration,env:GOATARG_DURATION" help:"Maximum duration of the load test"`
+	Iterations   int           `arg:"-i,--iterations,env:GOATARG_ITERATIONS" help:"Total number of iterations executed by all virtual users"`
+	Rate         float64       `arg:"-r,--rate,env:GOATARG_RATE" help:"Number of iterations started per second"`
+	RampUp       time.Duration `arg:"--ramp-up,env:GOATARG_RAMPUP" help:"Duration over which the start of the virtual users is distributed"`
+}
+
+func (LoadArgs) Version() string {
+	return Args{}.Version()
+}
+
+func (LoadArgs) Description() string {
+	return "Executes the Tests section of a Goatfile repeatedly with multiple virtual users as load test."
+}
+
+// runLoad parses the given arguments of the load
+// sub command and executes the load test.
+func runLoad(osArgs []string) {
+	var args LoadArgs
+	argParser, err := arg.NewParser(arg.Config{Program: "goat load"}, &args)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed initializing argument parser")
+		return
+	}
+
+	err = argParser.Parse(osArgs)
+	if err == arg.ErrHelp {
+		argParser.WriteHelp(os.Stdout)
+		return
+	}
+	if err == arg.ErrVersion {
+		os.Stdout.WriteString(args.Version() + "\n")
+		return
+	}
+	if err != nil {
+		argParser.Fail(err.Error())
+		return
+	}
+
+	setupLogging(args.CommonArgs)
+
+	state, ok := initialState(args.CommonArgs)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM, os.Interrupt, os.Kill)
+	defer cancel()
+
+	exec, _, ok := newExecutor(ctx, args.CommonArgs, projectRoot([]string{args.Goatfile}), state)
+	if !ok {
+		return
+	}
+
+	applySeed(randomSeed(args.CommonArgs))
+
+	log.Info().
+		Field("vus", args.VirtualUsers).
+		Field("duration", args.Duration).
+		Field("iterations", args.Iterations).
+		Field("rate", args.Rate).
+		Field("rampup", args.RampUp).
+		Msg(clr.Print(clr.Format("Starting load test ...", clr.ColorFGPurple, clr.FormatBold)))
+
+	report, err := exec.Load(args.Goatfile, state, executor.LoadOptions{
+		VirtualUsers: args.VirtualUsers,
+		Duration:     args.Duration,
+		Iterations:   args.Iterations,
+		ArrivalRate:  args.Rate,
+		RampUp:       args.RampUp,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg(clr.Print(clr.Format("load test failed", clr.ColorFGRed, clr.FormatBold)))
+		return
+	}
+
+	if args.Json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.Write(os.Stdout)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed writing report")
+	}
+}
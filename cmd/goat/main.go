@@ -24,58 +24,83 @@ import (
 	"github.com/studio-b12/goat/pkg/advancer"
 	"github.com/studio-b12/goat/pkg/clr"
 	"github.com/studio-b12/goat/pkg/config"
+	"github.com/studio-b12/goat/pkg/debugger"
 	"github.com/studio-b12/goat/pkg/engine"
 	"github.com/studio-b12/goat/pkg/executor"
+	"github.com/studio-b12/goat/pkg/grpcrequester"
 	"github.com/studio-b12/goat/pkg/requester"
+	"github.com/studio-b12/goat/pkg/stdlib"
 	"github.com/zekrotja/rogu"
 	"github.com/zekrotja/rogu/level"
 	"github.com/zekrotja/rogu/log"
 )
 
+// CommonArgs contains the arguments shared
+// between the main command and sub commands.
+type CommonArgs struct {
+	AllowFs       bool        `arg:"--allow-fs,env:GOATARG_ALLOWFS" help:"Allow scripts to read and write files outside of the project root and artifacts directory"`
+	Arg           []string    `arg:"-a,--args,separate" help:"Pass params as key value arguments into the execution (format: key=value)"`
+	ArtifactsDir  string      `arg:"--artifacts-dir,env:GOATARG_ARTIFACTSDIR" help:"Directory files written by scripts are stored in"`
+	CACert        []string    `arg:"--cacert,separate,env:GOATARG_CACERT" help:"Root CA certificate file(s) used to verify server certificates"`
+	Cert          string      `arg:"--cert,env:GOATARG_CERT" help:"Client certificate file used for mutual TLS"`
+	Engine        string      `arg:"--engine,env:GOATARG_ENGINE" default:"goja" help:"Default script engine (goja or expr)"`
+	Json          bool        `arg:"--json,env:GOATARG_JSON" help:"Use JSON format instead of pretty console format for logging"`
+	Key           string      `arg:"--key,env:GOATARG_KEY" help:"Client key file used for mutual TLS"`
+	LogLevel      level.Level `arg:"-l,--loglevel,env:GOATARG_LOGLEVEL" default:"info" help:"Logging level"`
+	NoAbort       bool        `arg:"--no-abort,env:GOATARG_NOABORT" help:"Do not abort batch execution on error"`
+	NoColor       bool        `arg:"--no-color,env:GOATARG_NOCOLOR" help:"Supress colored log output"`
+	Now           time.Time   `arg:"--now,env:GOATARG_NOW" help:"Fix the time returned by time builtins to the given RFC 3339 timestamp"`
+	Params        []string    `arg:"-p,--params,separate,env:GOATARG_PARAMS" help:"Params file location(s)"`
+	Profile       []string    `arg:"-P,--profile,separate,env:GOATARG_PROFILE" help:"Select a profile from your home config"`
+	Proxy         string      `arg:"--proxy,env:GOATARG_PROXY" help:"HTTP or SOCKS5 proxy URL used for requests"`
+	ReducedErrors bool        `arg:"-R,--reduced-errors,env:GOATARG_REDUCEDERRORS" help:"Hide template errors in teardown steps"`
+	Resolve       []string    `arg:"--resolve,separate,env:GOATARG_RESOLVE" help:"Resolve host and port to the given address (format: host:port:addr)"`
+	ScriptLib     string      `arg:"--script-lib,env:GOATARG_SCRIPTLIB" help:"Directory of JavaScript modules preloaded into every script engine"`
+	Secure        bool        `arg:"--secure,env:GOATARG_SECURE" help:"Validate TLS certificates"`
+	Seed          *int64      `arg:"--seed,env:GOATARG_SEED" help:"Seed for all random data generated in templates and scripts (random if not set)"`
+	Silent        bool        `arg:"-s,--silent,env:GOATARG_SILENT" help:"Disables all logging output"`
+	Skip          []string    `arg:"--skip,separate,env:GOATARG_SKIP" help:"Section(s) to be skipped during execution"`
+	TLSMinVersion string      `arg:"--tls-min-version,env:GOATARG_TLSMINVERSION" help:"Minimum accepted TLS version (1.0, 1.1, 1.2 or 1.3)"`
+	TLSServerName string      `arg:"--tls-server-name,env:GOATARG_TLSSERVERNAME" help:"Override the server name used for SNI and certificate verification"`
+	Transpile     bool        `arg:"--transpile,env:GOATARG_TRANSPILE" help:"Transpile modern JavaScript and TypeScript in scripts using esbuild before execution"`
+	UnixSocket    string      `arg:"--unix-socket,env:GOATARG_UNIXSOCKET" help:"Connect to the server via the given unix domain socket"`
+}
+
 type Args struct {
 	Goatfile []string `arg:"positional" help:"Goatfile(s) location"`
 
-	Arg           []string      `arg:"-a,--args,separate" help:"Pass params as key value arguments into the execution (format: key=value)"`
-	Delay         time.Duration `arg:"-d,--delay,env:GOATARG_DELAY" help:"Delay requests by the given duration"`
-	Dry           bool          `arg:"--dry" help:"Only parse the goatfile(s) without executing any requests"`
-	Gradual       bool          `arg:"-g,--gradual" help:"Advance the requests maually"`
-	Json          bool          `arg:"--json,env:GOATARG_JSON" help:"Use JSON format instead of pretty console format for logging"`
-	LogLevel      level.Level   `arg:"-l,--loglevel,env:GOATARG_LOGLEVEL" default:"info" help:"Logging level"`
-	New           bool          `arg:"--new" help:"Create a new base Goatfile"`
-	NoAbort       bool          `arg:"--no-abort,env:GOATARG_NOABORT" help:"Do not abort batch execution on error"`
-	NoColor       bool          `arg:"--no-color,env:GOATARG_NOCOLOR" help:"Supress colored log output"`
-	Params        []string      `arg:"-p,--params,separate,env:GOATARG_PARAMS" help:"Params file location(s)"`
-	Profile       []string      `arg:"-P,--profile,separate,env:GOATARG_PROFILE" help:"Select a profile from your home config"`
-	ReducedErrors bool          `arg:"-R,--reduced-errors,env:GOATARG_REDUCEDERRORS" help:"Hide template errors in teardown steps"`
-	Secure        bool          `arg:"--secure,env:GOATARG_SECURE" help:"Validate TLS certificates"`
-	Silent        bool          `arg:"-s,--silent,env:GOATARG_SILENT" help:"Disables all logging output"`
-	Skip          []string      `arg:"--skip,separate,env:GOATARG_SKIP" help:"Section(s) to be skipped during execution"`
-	RetryFailed   bool          `arg:"--retry-failed,env:GOATARG_RETRYFAILED" help:"Retry files which have failed in the previous run"`
+	CommonArgs
+
+	Break       []debugger.Breakpoint `arg:"--break,separate" help:"Pause the execution before the request at the given position (file.goat:line)"`
+	CookiesFile string                `arg:"--cookies-file,env:GOATARG_COOKIESFILE" help:"Load cookie jars from and store them to the given file"`
+	Delay       time.Duration         `arg:"-d,--delay,env:GOATARG_DELAY" help:"Delay requests by the given duration"`
+	Dry         bool                  `arg:"--dry" help:"Only parse the goatfile(s) without executing any requests"`
+	GlobalSetup string                `arg:"--global-setup,env:GOATARG_GLOBALSETUP" help:"Goatfile executed once before all batches whose state is shared with them"`
+	Gradual     bool                  `arg:"-g,--gradual" help:"Pause the execution before every request in the interactive debugger"`
+	New         bool                  `arg:"--new" help:"Create a new base Goatfile"`
+	NoReport    bool                  `arg:"--no-report,env:GOATARG_NOREPORT" help:"Do not print a report of the failed requests at the end of the run"`
+	ReportBody  int                   `arg:"--report-body,env:GOATARG_REPORTBODY" default:"1024" help:"Maximum number of bytes of bodies shown in the failure report (0 for no truncation)"`
+	ReportLines int                   `arg:"--report-lines,env:GOATARG_REPORTLINES" default:"2" help:"Number of script lines shown around the failing line in the failure report"`
+	RetryFailed bool                  `arg:"--retry-failed,env:GOATARG_RETRYFAILED" help:"Retry files which have failed in the previous run"`
+	Watch       bool                  `arg:"-w,--watch" help:"Watch the Goatfiles and their dependencies and re-run affected Goatfiles on change"`
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "load":
+			runLoad(os.Args[2:])
+			return
+		case "repl":
+			runRepl(os.Args[2:])
+			return
+		}
+	}
 
 	var args Args
 	argParser := arg.MustParse(&args)
 
-	if args.Silent {
-		log.SetLevel(level.Off)
-	} else {
-		log.SetLevel(args.LogLevel)
-	}
-
-	if args.Json {
-		w := rogu.NewJsonWriter(os.Stdout)
-		log.SetWriter(w)
-	} else {
-		w := rogu.NewPrettyWriter(os.Stdout)
-		w.NoColor = args.NoColor
-		w.TimeFormat = time.RFC3339
-		w.StyleTag.Width(20)
-		log.SetWriter(w)
-	}
-
-	clr.SetEnable(!args.Json && !args.NoColor)
+	setupLogging(args.CommonArgs)
 
 	if args.New {
 		createNewGoatfile(args.Goatfile)
@@ -103,62 +128,287 @@ func main() {
 		return
 	}
 
-	state := make(engine.State)
+	state, ok := initialState(args.CommonArgs)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM, os.Interrupt, os.Kill)
+	defer cancel()
+
+	exec, req, ok := newExecutor(ctx, args.CommonArgs, projectRoot(goatfiles), state)
+	if !ok {
+		return
+	}
+
+	if args.CookiesFile != "" {
+		err := loadCookies(req, args.CookiesFile)
+		if err != nil {
+			log.Fatal().Err(err).Field("file", args.CookiesFile).Msg("Failed loading cookies")
+			return
+		}
+	}
+
+	exec.Dry = args.Dry
+	exec.GlobalSetup = args.GlobalSetup
+
+	if args.Gradual || len(args.Break) > 0 {
+		dbg := debugger.New(os.Stdin, os.Stdout, cancel)
+		dbg.Step = args.Gradual
+		dbg.Breakpoints = args.Break
+		exec.Debugger = dbg
+		log.Info().Msg("Debug mode: Enter 'help' when paused to list all commands.")
+	}
+
+	if args.Delay != 0 {
+		log.Info().Msgf("Delay mode: Advancing every %s", args.Delay.String())
+		exec.Waiter = advancer.NewTicker(args.Delay)
+	}
+
+	log.Debug().Msgf("Initial Params\n%s", state)
+
+	seed, explicitSeed := randomSeed(args.CommonArgs)
+
+	if args.Watch {
+		applySeed(seed, explicitSeed)
+		exec.Watch(goatfiles, state, !args.ReducedErrors, executor.WatchOptions{
+			Interval: 200 * time.Millisecond,
+			Debounce: 300 * time.Millisecond,
+			BeforeRun: func(changed []string) {
+				clearTerminal(args.CommonArgs)
+				log.Info().Field("changed", changed).Msg("Files have changed, re-running affected Goatfiles ...")
+				applySeed(seed, explicitSeed)
+			},
+			AfterRun: func(res executor.Result, err error) {
+				logResult(args, req, res, err, false)
+			},
+		})
+		return
+	}
+
+	applySeed(seed, explicitSeed)
+	res, err := exec.Execute(goatfiles, state, !args.ReducedErrors)
+	logResult(args, req, res, err, true)
+}
+
+// setupLogging configures the logger and the
+// colored output according to the given args.
+func setupLogging(args CommonArgs) {
+	if args.Silent {
+		log.SetLevel(level.Off)
+	} else {
+		log.SetLevel(args.LogLevel)
+	}
+
+	if args.Json {
+		w := rogu.NewJsonWriter(os.Stdout)
+		log.SetWriter(w)
+	} else {
+		w := rogu.NewPrettyWriter(os.Stdout)
+		w.NoColor = args.NoColor
+		w.TimeFormat = time.RFC3339
+		w.StyleTag.Width(20)
+		log.SetWriter(w)
+	}
+
+	clr.SetEnable(!args.Json && !args.NoColor)
+}
+
+// initialState assembles the initial state from the selected
+// profiles, the parameter files and the passed arguments. If
+// this fails, the error is logged and ok is false.
+func initialState(args CommonArgs) (state engine.State, ok bool) {
+	state = make(engine.State)
 
 	err := config.LoadProfiles(args.Profile, state)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed loading profiles")
-		return
+		return nil, false
 	}
 
 	cfgState, err := config.Parse[engine.State](args.Params, "GOAT_")
 	if err != nil {
 		log.Fatal().Err(err).Msg("parameter parsing failed")
-		return
+		return nil, false
 	}
 	state.Merge(cfgState)
 
 	err = config.ParseKVArgs(args.Arg, state)
 	if err != nil {
 		log.Fatal().Err(err).Msg("argument parsing failed")
-		return
+		return nil, false
+	}
+
+	return state, true
+}
+
+// newExecutor creates the requesters and the executor configured
+// by the given args and state. Scripts can read files and modules
+// from the given project root. If root is empty, the directory of
+// the executed Goatfile is used. If this fails, the error is logged
+// and ok is false.
+func newExecutor(
+	ctx context.Context,
+	args CommonArgs,
+	root string,
+	state engine.State,
+) (exec *executor.Executor, req *requester.HttpWithCookies, ok bool) {
+	tlsOpts := tlsOptions(args, state)
+	tlsConfig, err := tlsOpts.Config(&tls.Config{
+		InsecureSkipVerify: !args.Secure,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid TLS configuration")
+		return nil, nil, false
 	}
 
-	engineMaker := engine.NewGoja
-	req := requester.NewHttpWithCookies(func(client *http.Client) {
+	if !args.Now.IsZero() {
+		stdlib.SetNow(args.Now)
+		log.Info().Field("now", args.Now.Format(time.RFC3339)).Msg("Time builtins are fixed to the given time")
+	}
+
+	modules := engine.NewModules()
+	engines := map[string]func() engine.Engine{
+		"goja": func() engine.Engine {
+			return engine.NewGojaWithOptions(engine.GojaOptions{
+				Modules: modules,
+				Files: engine.FileAccess{
+					Root:         root,
+					ArtifactsDir: args.ArtifactsDir,
+					Unrestricted: args.AllowFs,
+				},
+				Transpile: args.Transpile,
+			})
+		},
+		"expr": engine.NewExpr,
+	}
+	if args.ScriptLib != "" {
+		if err = modules.SetLibDir(args.ScriptLib); err == nil {
+			err = engines["goja"]().Run("")
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed loading script library")
+			return nil, nil, false
+		}
+	}
+
+	engineMaker, ok := engines[args.Engine]
+	if !ok {
+		log.Fatal().Field("engine", args.Engine).Msg("Unknown script engine")
+		return nil, nil, false
+	}
+
+	req = requester.NewHttpWithCookies(func(client *http.Client) {
 		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: !args.Secure,
 		}}
 	})
+	req.SetDefaultTransportOptions(requester.TransportOptions{
+		TLS:        tlsOpts,
+		Proxy:      args.Proxy,
+		UnixSocket: args.UnixSocket,
+		Resolve:    args.Resolve,
+	})
 
-	ctx, cancel := signal.NotifyContext(context.Background(),
-		syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM, os.Interrupt, os.Kill)
-	defer cancel()
-
-	exec := executor.New(ctx, engineMaker, req)
-	exec.Dry = args.Dry
+	exec = executor.New(ctx, engineMaker, req)
+	exec.Engines = engines
+	exec.GrpcRequester = grpcrequester.NewClient(tlsConfig)
 	exec.Skip = args.Skip
 	exec.NoAbort = args.NoAbort
 
-	if args.Gradual {
-		ad := make(advancer.Channel)
-		exec.Waiter = ad
-		go advanceManually(ad)
-	} else if args.Delay != 0 {
-		log.Info().Msgf("Delay mode: Advancing every %s", args.Delay.String())
-		exec.Waiter = advancer.NewTicker(args.Delay)
+	return exec, req, true
+}
+
+// projectRoot returns the deepest directory containing
+// all of the given Goatfiles and directories.
+func projectRoot(goatfiles []string) string {
+	var root string
+	for _, path := range goatfiles {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		if stat, err := os.Stat(path); err != nil || !stat.IsDir() {
+			path = filepath.Dir(path)
+		}
+
+		if root == "" {
+			root = path
+			continue
+		}
+		for !isSubPath(root, path) {
+			root = filepath.Dir(root)
+		}
 	}
+	return root
+}
 
-	log.Debug().Msgf("Initial Params\n%s", state)
+// isSubPath returns true if path is located in dir.
+func isSubPath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
 
-	res, err := exec.Execute(goatfiles, state, !args.ReducedErrors)
+// randomSeed returns the seed passed in the given args
+// or a new seed based on the current time if none has
+// been passed. explicit is true if the seed has been
+// passed in the args.
+func randomSeed(args CommonArgs) (seed int64, explicit bool) {
+	if args.Seed != nil {
+		return *args.Seed, true
+	}
+	return time.Now().UnixNano(), false
+}
+
+// applySeed seeds the random number generator used by
+// templates and scripts with the given seed and logs it,
+// so that the run can be reproduced using --seed. Random
+// bytes and UUIDs are only derived from the seed if it
+// has been passed explicitly.
+func applySeed(seed int64, explicit bool) {
+	if explicit {
+		stdlib.Seed(seed)
+	} else {
+		stdlib.SeedDefault(seed)
+	}
+	log.Info().Field("seed", seed).Msg("Seeded random data (pass --seed to reproduce)")
+}
+
+// logResult logs the result of an execution and stores the cookies
+// and the failed files if requested. If fatal is true, the program
+// exits with a non-zero exit code when err is not nil.
+func logResult(args Args, req *requester.HttpWithCookies, res executor.Result, err error, fatal bool) {
 	res.Log()
+
+	if args.CookiesFile != "" {
+		if sErr := storeCookies(req, args.CookiesFile); sErr != nil {
+			log.Error().Err(sErr).Field("file", args.CookiesFile).Msg("Failed storing cookies")
+		}
+	}
 	if err != nil {
 		if args.ReducedErrors {
 			err = filterTeardownParamErrors(err)
 		}
 
-		entry := log.Fatal().Err(err)
+		if !args.NoReport && !args.Json && !args.Silent {
+			if reqErrs := executor.RequestErrors(err); len(reqErrs) > 0 {
+				fmt.Fprintln(os.Stdout)
+				rErr := executor.WriteFailureReport(os.Stdout, reqErrs, executor.ReportOptions{
+					BodyLimit:    args.ReportBody,
+					ContextLines: args.ReportLines,
+				})
+				if rErr != nil {
+					log.Error().Err(rErr).Msg("Failed writing failure report")
+				}
+			}
+		}
+
+		entry := log.Error()
+		if fatal {
+			entry = log.Fatal()
+		}
+		entry = entry.Err(err)
 
 		if batchErr, ok := errs.As[*executor.BatchResultError](err); ok {
 			if sErr := storeLastFailedFiles(batchErr.FailedFiles()); err != nil {
@@ -179,6 +429,18 @@ func main() {
 	log.Info().Msg(clr.Print(clr.Format("Execution finished successfully", clr.ColorFGGreen, clr.FormatBold)))
 }
 
+// clearTerminal clears the terminal screen when the
+// output is printed in pretty format to a terminal.
+func clearTerminal(args CommonArgs) {
+	if args.Json || args.Silent {
+		return
+	}
+	if stat, err := os.Stdout.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+	fmt.Fprint(os.Stdout, "\033[H\033[2J\033[3J")
+}
+
 func (Args) Description() string {
 	return "Automation tool for executing and evaluating API requests."
 }
@@ -188,28 +450,23 @@ func (Args) Version() string {
 		version.Version, version.CommitHash, version.BuildDate, runtime.Version())
 }
 
-func advanceManually(a advancer.Advancer) {
-	scanner := bufio.NewScanner(os.Stdin)
-	log.Info().Msg(
-		"Gradual mode: Press [enter] to advance requests, " +
-			"enter 'quit' / 'q' to quit the execution or " +
-			"enter 'continue' / 'cont' / 'c' to stop gradual advancement.")
-
-	var skip bool
-	for {
-		if !skip {
-			scanner.Scan()
-			txt := scanner.Text()
-			switch strings.ToLower(txt) {
-			case "quit", "q":
-				log.Warn().Msg("Aborted.")
-				os.Exit(1)
-			case "continue", "cont", "c":
-				skip = true
-			}
-		}
-		a.Advance()
+// tlsOptions returns the TLS options specified in the
+// 'tls' parameter, which can be defined in profiles or
+// parameter files, overwritten by the values passed
+// via CLI flags.
+func tlsOptions(args CommonArgs, state engine.State) requester.TLSOptions {
+	var opt requester.TLSOptions
+	if m, ok := state["tls"].(map[string]any); ok {
+		opt = requester.TLSOptionsFromMap(m)
 	}
+
+	return requester.TLSOptions{
+		CertFile:   args.Cert,
+		KeyFile:    args.Key,
+		CAFiles:    args.CACert,
+		MinVersion: args.TLSMinVersion,
+		ServerName: args.TLSServerName,
+	}.Merge(opt)
 }
 
 func createNewGoatfile(names []string) {
@@ -265,6 +522,29 @@ func filterTeardownParamErrors(err error) error {
 	}
 }
 
+func loadCookies(req *requester.HttpWithCookies, file string) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return req.LoadCookies(f)
+}
+
+func storeCookies(req *requester.HttpWithCookies, file string) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return req.SaveCookies(f)
+}
+
 const lastFailedRunFileName = "goat_last_failed_run"
 
 func storeLastFailedFiles(paths []string) error {
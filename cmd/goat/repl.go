@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/alexflint/go-arg"
+	"github.com/studio-b12/goat/pkg/repl"
+	"github.com/zekrotja/rogu/log"
+)
+
+type ReplArgs struct {
+	CommonArgs
+
+	CookiesFile string `arg:"--cookies-file,env:GOATARG_COOKIESFILE" help:"Load cookie jars from and store them to the given file"`
+	HistoryFile string `arg:"--history-file,env:GOATARG_HISTORYFILE" help:"File the history is stored in (defaults to 'goat/repl_history' in the user config directory)"`
+}
+
+func (ReplArgs) Version() string {
+	return Args{}.Version()
+}
+
+func (ReplArgs) Description() string {
+	return "Interactively executes Goatfile snippets and JavaScript in a persistent session."
+}
+
+// runRepl parses the given arguments of the repl
+// sub command and runs the REPL.
+func runRepl(osArgs []string) {
+	var args ReplArgs
+	argParser, err := arg.NewParser(arg.Config{Program: "goat repl"}, &args)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed initializing argument parser")
+		return
+	}
+
+	err = argParser.Parse(osArgs)
+	if err == arg.ErrHelp {
+		argParser.WriteHelp(os.Stdout)
+		return
+	}
+	if err == arg.ErrVersion {
+		os.Stdout.WriteString(args.Version() + "\n")
+		return
+	}
+	if err != nil {
+		argParser.Fail(err.Error())
+		return
+	}
+
+	setupLogging(args.CommonArgs)
+
+	state, ok := initialState(args.CommonArgs)
+	if !ok {
+		return
+	}
+
+	exec, req, ok := newExecutor(context.Background(), args.CommonArgs, "", state)
+	if !ok {
+		return
+	}
+
+	applySeed(randomSeed(args.CommonArgs))
+
+	if args.CookiesFile != "" {
+		err = loadCookies(req, args.CookiesFile)
+		if err != nil {
+			log.Fatal().Err(err).Field("file", args.CookiesFile).Msg("Failed loading cookies")
+			return
+		}
+	}
+
+	historyFile := args.HistoryFile
+	if historyFile == "" {
+		if configDir, err := os.UserConfigDir(); err == nil {
+			historyFile = filepath.Join(configDir, "goat", "repl_history")
+		}
+	}
+
+	history, err := repl.LoadHistory(historyFile)
+	if err != nil {
+		log.Fatal().Err(err).Field("file", historyFile).Msg("Failed loading history")
+		return
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed getting working directory")
+		return
+	}
+
+	repl.New(os.Stdin, os.Stdout, exec.NewSession(state), history, dir).Run()
+
+	if args.CookiesFile != "" {
+		if err = storeCookies(req, args.CookiesFile); err != nil {
+			log.Error().Err(err).Field("file", args.CookiesFile).Msg("Failed storing cookies")
+		}
+	}
+}
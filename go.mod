@@ -6,13 +6,18 @@ toolchain go1.23.4
 
 require (
 	github.com/alexflint/go-arg v1.5.1
+	github.com/bufbuild/protocompile v0.14.1
 	github.com/dop251/goja v0.0.0-20250125213203-5ef83b82af17
+	github.com/evanw/esbuild v0.25.10
+	github.com/expr-lang/expr v1.17.8
 	github.com/golang/mock v1.6.0
 	github.com/itchyny/gojq v0.12.17
 	github.com/joho/godotenv v1.5.1
-	github.com/stretchr/testify v1.8.1
+	github.com/stretchr/testify v1.9.0 // required by github.com/bufbuild/protocompile v0.14.1
 	github.com/traefik/paerser v0.2.1
 	github.com/zekrotja/rogu v0.8.0
+	google.golang.org/grpc v1.64.1
+	google.golang.org/protobuf v1.34.2
 )
 
 require (
@@ -33,7 +38,10 @@ require (
 	github.com/muesli/termenv v0.15.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,77 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+var ErrInvalidBreakpoint = errors.New("invalid breakpoint: must be in the format 'file:line'")
+
+// Breakpoint pauses the execution before the request
+// defined at the given line in the given Goatfile.
+type Breakpoint struct {
+	File string
+	Line int
+}
+
+// ParseBreakpoint parses a Breakpoint from the
+// format 'file:line'.
+func ParseBreakpoint(s string) (t Breakpoint, err error) {
+	i := strings.LastIndex(s, ":")
+	if i < 1 {
+		return Breakpoint{}, ErrInvalidBreakpoint
+	}
+
+	t.File = s[:i]
+	t.Line, err = strconv.Atoi(s[i+1:])
+	if err != nil || t.Line < 1 {
+		return Breakpoint{}, ErrInvalidBreakpoint
+	}
+
+	return t, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+// using ParseBreakpoint.
+func (t *Breakpoint) UnmarshalText(text []byte) (err error) {
+	*t, err = ParseBreakpoint(string(text))
+	return err
+}
+
+func (t Breakpoint) String() string {
+	return fmt.Sprintf("%s:%d", t.File, t.Line)
+}
+
+// Matches returns true if the given request is defined
+// at the line of the breakpoint. The file of the breakpoint
+// matches when the path of the request ends with it. The
+// file extension of the breakpoint file can be omitted.
+// Absolute and relative paths are compared as well.
+func (t Breakpoint) Matches(req *goatfile.Request) bool {
+	if req == nil || req.PosLine != t.Line {
+		return false
+	}
+
+	file := filepath.Clean(goatfile.Extend(t.File, goatfile.FileExtension))
+	reqPath := filepath.Clean(req.Path)
+
+	if reqPath == file || strings.HasSuffix(reqPath, string(filepath.Separator)+file) {
+		return true
+	}
+
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return false
+	}
+	absReqPath, err := filepath.Abs(reqPath)
+	if err != nil {
+		return false
+	}
+
+	return absReqPath == absFile
+}
@@ -0,0 +1,37 @@
+package debugger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+func TestParseBreakpoint(t *testing.T) {
+	bp, err := ParseBreakpoint("tests/login.goat:30")
+	assert.Nil(t, err)
+	assert.Equal(t, Breakpoint{File: "tests/login.goat", Line: 30}, bp)
+
+	bp, err = ParseBreakpoint(`C:\tests\login.goat:4`)
+	assert.Nil(t, err)
+	assert.Equal(t, Breakpoint{File: `C:\tests\login.goat`, Line: 4}, bp)
+
+	for _, invalid := range []string{"", "login.goat", ":30", "login.goat:", "login.goat:abc", "login.goat:0"} {
+		_, err = ParseBreakpoint(invalid)
+		assert.ErrorIs(t, err, ErrInvalidBreakpoint, invalid)
+	}
+}
+
+func TestBreakpoint_Matches(t *testing.T) {
+	req := &goatfile.Request{Path: "tests/auth/login.goat", PosLine: 12}
+
+	assert.True(t, Breakpoint{File: "tests/auth/login.goat", Line: 12}.Matches(req))
+	assert.True(t, Breakpoint{File: "./tests/auth/login.goat", Line: 12}.Matches(req))
+	assert.True(t, Breakpoint{File: "auth/login.goat", Line: 12}.Matches(req))
+	assert.True(t, Breakpoint{File: "login", Line: 12}.Matches(req))
+
+	assert.False(t, Breakpoint{File: "login.goat", Line: 13}.Matches(req))
+	assert.False(t, Breakpoint{File: "gin.goat", Line: 12}.Matches(req))
+	assert.False(t, Breakpoint{File: "other/login.goat", Line: 12}.Matches(req))
+	assert.False(t, Breakpoint{File: "login.goat", Line: 12}.Matches(nil))
+}
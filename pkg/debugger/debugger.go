@@ -0,0 +1,273 @@
+// Package debugger provides an interactive terminal
+// debugger which pauses the execution of Goatfiles
+// before requests.
+package debugger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/clr"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/executor"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+const helpText = `Commands:
+  [enter], next, n     Send the request and pause before the next one
+  continue, c          Continue until the next breakpoint
+  skip, s              Skip the request (only before the request)
+  resend               Send the request again (only after the response)
+  request, r           Show the request
+  response             Show the response (only after the response)
+  edit                 Edit the request in an editor (only before the request)
+  state                Show all state variables
+  get <name>           Show the value of a state variable
+  set <name> <expr>    Set a state variable to the result of a JavaScript expression
+  eval <expr>, e       Evaluate a JavaScript expression
+  quit, q              Abort the execution
+  help, h              Show this help`
+
+// Terminal implements executor.Debugger reading
+// commands from an input and writing to an output.
+type Terminal struct {
+	in     *bufio.Scanner
+	out    io.Writer
+	cancel func()
+	done   bool
+
+	// Step pauses the execution before every request.
+	// Otherwise, the execution is only paused at
+	// breakpoints. After a breakpoint has been hit,
+	// the execution is paused before every request
+	// until 'continue' is entered.
+	Step bool
+
+	// Breakpoints pause the execution before the
+	// requests at the given positions.
+	Breakpoints []Breakpoint
+
+	// Editor is the command used to edit requests.
+	// It defaults to the EDITOR environment variable
+	// or 'vi' if not set.
+	Editor string
+}
+
+var _ executor.Debugger = (*Terminal)(nil)
+
+// New returns a new Terminal reading commands from in
+// and writing to out. cancel is called when the execution
+// is aborted by the user.
+func New(in io.Reader, out io.Writer, cancel func()) *Terminal {
+	var t Terminal
+
+	t.in = bufio.NewScanner(in)
+	t.out = out
+	t.cancel = cancel
+	t.Editor = os.Getenv("EDITOR")
+	if t.Editor == "" {
+		t.Editor = "vi"
+	}
+
+	return &t
+}
+
+func (t *Terminal) BeforeRequest(req *goatfile.Request, eng engine.Engine) executor.DebugAction {
+	if t.done || !t.Step && !t.isBreakpoint(req) {
+		return executor.DebugContinue
+	}
+
+	t.Step = true
+
+	t.printf("%s %s\n\n",
+		clr.Print(clr.Format("Paused before request", clr.ColorFGPurple, clr.FormatBold)),
+		clr.Print(clr.Format(fmt.Sprintf("(%s:%d)", req.Path, req.PosLine), clr.ColorFGBlack)))
+	t.printf("%s\n", formatRequest(req))
+
+	for {
+		cmd, arg, ok := t.prompt()
+		if !ok {
+			return t.stop()
+		}
+
+		switch cmd {
+		case "", "next", "n":
+			return executor.DebugContinue
+		case "continue", "c":
+			t.Step = false
+			return executor.DebugContinue
+		case "skip", "s":
+			return executor.DebugSkip
+		case "quit", "q":
+			t.abort()
+			return executor.DebugSkip
+		case "edit":
+			if err := editRequest(t.Editor, req); err != nil {
+				t.printError(err)
+				continue
+			}
+			t.printf("%s\n", formatRequest(req))
+		default:
+			t.command(cmd, arg, req, nil, eng)
+		}
+	}
+}
+
+func (t *Terminal) AfterResponse(req *goatfile.Request, resp executor.Response, err error, eng engine.Engine) executor.DebugAction {
+	if t.done || !t.Step {
+		return executor.DebugContinue
+	}
+
+	t.printf("%s\n\n", clr.Print(clr.Format("Paused after response", clr.ColorFGPurple, clr.FormatBold)))
+	if err != nil {
+		t.printError(err)
+	} else {
+		t.printResponse(resp)
+	}
+
+	for {
+		cmd, arg, ok := t.prompt()
+		if !ok {
+			return t.stop()
+		}
+
+		switch cmd {
+		case "", "next", "n":
+			return executor.DebugContinue
+		case "continue", "c":
+			t.Step = false
+			return executor.DebugContinue
+		case "resend":
+			return executor.DebugResend
+		case "quit", "q":
+			t.abort()
+			return executor.DebugContinue
+		case "response":
+			if err != nil {
+				t.printError(err)
+			} else {
+				t.printResponse(resp)
+			}
+		default:
+			t.command(cmd, arg, req, &resp, eng)
+		}
+	}
+}
+
+// command executes the commands which are available
+// before the request as well as after the response.
+func (t *Terminal) command(cmd, arg string, req *goatfile.Request, resp *executor.Response, eng engine.Engine) {
+	switch cmd {
+	case "request", "r":
+		t.printf("%s\n", formatRequest(req))
+	case "state":
+		t.printValue(eng.State())
+	case "get":
+		v, ok := eng.State()[arg]
+		if !ok {
+			t.printf("%s is not set\n", arg)
+			return
+		}
+		t.printValue(v)
+	case "set":
+		name, expr, _ := strings.Cut(arg, " ")
+		if name == "" || strings.TrimSpace(expr) == "" {
+			t.printf("usage: set <name> <expr>\n")
+			return
+		}
+		v, err := eng.Eval(expr)
+		if err != nil {
+			t.printError(err)
+			return
+		}
+		if err = eng.Set(name, v); err != nil {
+			t.printError(err)
+		}
+	case "eval", "e":
+		v, err := eng.Eval(arg)
+		if err != nil {
+			t.printError(err)
+			return
+		}
+		t.printValue(v)
+	case "help", "h":
+		t.printf("%s\n", helpText)
+	case "skip", "s", "edit":
+		t.printf("%s is only available before the request\n", cmd)
+	case "resend", "response":
+		t.printf("%s is only available after the response\n", cmd)
+	default:
+		t.printf("unknown command %q, enter 'help' to list all commands\n", cmd)
+	}
+}
+
+func (t *Terminal) isBreakpoint(req *goatfile.Request) bool {
+	for _, bp := range t.Breakpoints {
+		if bp.Matches(req) {
+			return true
+		}
+	}
+	return false
+}
+
+// prompt reads the next command from the input. ok
+// is false if the input has been closed.
+func (t *Terminal) prompt() (cmd, arg string, ok bool) {
+	t.printf("%s ", clr.Print(clr.Format("(goat)", clr.ColorFGCyan)))
+	if !t.in.Scan() {
+		return "", "", false
+	}
+
+	cmd, arg, _ = strings.Cut(strings.TrimSpace(t.in.Text()), " ")
+	return strings.ToLower(cmd), strings.TrimSpace(arg), true
+}
+
+// stop stops pausing the execution when the
+// input has been closed.
+func (t *Terminal) stop() executor.DebugAction {
+	t.printf("\n")
+	t.done = true
+	return executor.DebugContinue
+}
+
+// abort stops pausing the execution and cancels it.
+func (t *Terminal) abort() {
+	t.done = true
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
+func (t *Terminal) printResponse(resp executor.Response) {
+	t.printf("%s %s\n", resp.Proto, resp.Status)
+	for _, key := range sortedKeys(resp.Header) {
+		for _, v := range resp.Header[key] {
+			t.printf("%s: %s\n", key, v)
+		}
+	}
+	if len(resp.BodyRaw) > 0 {
+		t.printf("\n%s\n", strings.TrimSuffix(resp.BodyRaw.String(), "\n"))
+	}
+	t.printf("\n")
+}
+
+func (t *Terminal) printValue(v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.printf("%v\n", v)
+		return
+	}
+	t.printf("%s\n", data)
+}
+
+func (t *Terminal) printError(err error) {
+	t.printf("%s %s\n", clr.Print(clr.Format("error:", clr.ColorFGRed, clr.FormatBold)), err.Error())
+}
+
+func (t *Terminal) printf(format string, v ...any) {
+	fmt.Fprintf(t.out, format, v...)
+}
@@ -0,0 +1,122 @@
+package debugger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/executor"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+func TestTerminal_BeforeRequest(t *testing.T) {
+	req := &goatfile.Request{Method: "GET", URI: "https://example.com", Path: "test.goat", PosLine: 3}
+
+	t.Run("not-paused", func(t *testing.T) {
+		var out strings.Builder
+		dbg := New(strings.NewReader("s\n"), &out, nil)
+
+		assert.Equal(t, executor.DebugContinue, dbg.BeforeRequest(req, engine.NewGoja()))
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("state", func(t *testing.T) {
+		var out strings.Builder
+		eng := engine.NewGoja()
+		eng.SetState(engine.State{"foo": "bar"})
+
+		dbg := New(strings.NewReader("get foo\nset foo 'baz'.toUpperCase()\neval foo + '!'\nget missing\nresend\nunknown\n\n"), &out, nil)
+		dbg.Step = true
+
+		assert.Equal(t, executor.DebugContinue, dbg.BeforeRequest(req, eng))
+		assert.Equal(t, "BAZ", eng.State()["foo"])
+		assert.Contains(t, out.String(), "GET https://example.com")
+		assert.Contains(t, out.String(), `"bar"`)
+		assert.Contains(t, out.String(), `"BAZ!"`)
+		assert.Contains(t, out.String(), "missing is not set")
+		assert.Contains(t, out.String(), "resend is only available after the response")
+		assert.Contains(t, out.String(), `unknown command "unknown"`)
+		assert.True(t, dbg.Step)
+	})
+
+	t.Run("breakpoint", func(t *testing.T) {
+		var out strings.Builder
+		dbg := New(strings.NewReader("c\n"), &out, nil)
+		dbg.Breakpoints = []Breakpoint{{File: "test.goat", Line: 3}}
+
+		assert.Equal(t, executor.DebugContinue, dbg.BeforeRequest(req, engine.NewGoja()))
+		assert.Contains(t, out.String(), "Paused before request")
+		assert.False(t, dbg.Step)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		var out strings.Builder
+		dbg := New(strings.NewReader("skip\n"), &out, nil)
+		dbg.Step = true
+
+		assert.Equal(t, executor.DebugSkip, dbg.BeforeRequest(req, engine.NewGoja()))
+	})
+
+	t.Run("quit", func(t *testing.T) {
+		var out strings.Builder
+		canceled := false
+		dbg := New(strings.NewReader("q\n"), &out, func() { canceled = true })
+		dbg.Step = true
+
+		assert.Equal(t, executor.DebugSkip, dbg.BeforeRequest(req, engine.NewGoja()))
+		assert.True(t, canceled)
+
+		// The execution is not paused anymore after quitting.
+		assert.Equal(t, executor.DebugContinue, dbg.BeforeRequest(req, engine.NewGoja()))
+	})
+
+	t.Run("closed-input", func(t *testing.T) {
+		var out strings.Builder
+		dbg := New(strings.NewReader(""), &out, nil)
+		dbg.Step = true
+
+		assert.Equal(t, executor.DebugContinue, dbg.BeforeRequest(req, engine.NewGoja()))
+		assert.Equal(t, executor.DebugContinue, dbg.AfterResponse(req, executor.Response{}, nil, engine.NewGoja()))
+	})
+}
+
+func TestTerminal_AfterResponse(t *testing.T) {
+	req := &goatfile.Request{Method: "GET", URI: "https://example.com"}
+	resp := executor.Response{
+		Proto:   "HTTP/1.1",
+		Status:  "200 OK",
+		Header:  map[string][]string{"Content-Type": {"text/plain"}},
+		BodyRaw: executor.RawData("hello"),
+	}
+
+	t.Run("resend", func(t *testing.T) {
+		var out strings.Builder
+		dbg := New(strings.NewReader("resend\nn\n"), &out, nil)
+		dbg.Step = true
+
+		assert.Equal(t, executor.DebugResend, dbg.AfterResponse(req, resp, nil, engine.NewGoja()))
+		assert.Equal(t, executor.DebugContinue, dbg.AfterResponse(req, resp, nil, engine.NewGoja()))
+		assert.Contains(t, out.String(), "HTTP/1.1 200 OK\nContent-Type: text/plain\n\nhello\n")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var out strings.Builder
+		dbg := New(strings.NewReader("edit\nc\n"), &out, nil)
+		dbg.Step = true
+
+		assert.Equal(t, executor.DebugContinue, dbg.AfterResponse(req, executor.Response{}, errors.New("connection refused"), engine.NewGoja()))
+		assert.Contains(t, out.String(), "connection refused")
+		assert.Contains(t, out.String(), "edit is only available before the request")
+		assert.False(t, dbg.Step)
+	})
+
+	t.Run("not-stepping", func(t *testing.T) {
+		var out strings.Builder
+		dbg := New(strings.NewReader("resend\n"), &out, nil)
+
+		assert.Equal(t, executor.DebugContinue, dbg.AfterResponse(req, resp, nil, engine.NewGoja()))
+		assert.Empty(t, out.String())
+	})
+}
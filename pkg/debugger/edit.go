@@ -0,0 +1,142 @@
+package debugger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+var ErrInvalidEdit = errors.New("edited content must contain exactly one request")
+
+// formatRequest renders the method, URL, query parameters,
+// header and string body of the given request in Goatfile
+// syntax.
+func formatRequest(req *goatfile.Request) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s %s\n", req.Method, req.URI)
+
+	if len(req.QueryParams) > 0 {
+		sb.WriteString("\n[QueryParams]\n")
+		for _, key := range sortedKeys(req.QueryParams) {
+			fmt.Fprintf(&sb, "%s = %s\n", key, formatValue(req.QueryParams[key]))
+		}
+	}
+
+	if len(req.Header) > 0 {
+		sb.WriteString("\n[Header]\n")
+		for _, key := range sortedKeys(req.Header) {
+			for _, v := range req.Header[key] {
+				fmt.Fprintf(&sb, "%s: %s\n", key, v)
+			}
+		}
+	}
+
+	switch body := req.Body.(type) {
+	case goatfile.StringContent:
+		fmt.Fprintf(&sb, "\n[Body]\n```\n%s\n```\n", strings.TrimSuffix(string(body), "\n"))
+	case goatfile.NoContent:
+	default:
+		sb.WriteString("\n// The body of this request can not be edited.\n")
+	}
+
+	return sb.String()
+}
+
+// applyEdit parses the given raw Goatfile request and applies
+// its method, URL, query parameters, header and body to req.
+//
+// The body is only replaced if the edited request contains
+// a body or if the body of req was editable.
+func applyEdit(req *goatfile.Request, raw string) error {
+	gf, err := goatfile.Unmarshal(raw, path.Dir(req.Path))
+	if err != nil {
+		return errs.WithPrefix("failed parsing edited request:", err)
+	}
+
+	if len(gf.Setup) != 0 || len(gf.Teardown) != 0 || len(gf.Tests) != 1 {
+		return ErrInvalidEdit
+	}
+
+	edited, ok := gf.Tests[0].(*goatfile.Request)
+	if !ok {
+		return ErrInvalidEdit
+	}
+
+	req.Method = edited.Method
+	req.URI = edited.URI
+	req.Header = edited.Header
+	req.QueryParams = edited.QueryParams
+
+	_, editable := req.Body.(goatfile.StringContent)
+	if editable || !goatfile.IsNoContent(edited.Body) {
+		req.Body = edited.Body
+	}
+
+	return nil
+}
+
+// editRequest opens the given request in the given editor
+// and applies the changes after the editor has been closed.
+func editRequest(editor string, req *goatfile.Request) error {
+	f, err := os.CreateTemp("", "goat-request-*.goat")
+	if err != nil {
+		return errs.WithPrefix("failed creating temporary file:", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(formatRequest(req))
+	f.Close()
+	if err != nil {
+		return errs.WithPrefix("failed writing temporary file:", err)
+	}
+
+	command := strings.Fields(editor)
+	if len(command) == 0 {
+		return errors.New("no editor has been specified")
+	}
+
+	cmd := exec.Command(command[0], append(command[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		return errs.WithPrefix("editor failed:", err)
+	}
+
+	raw, err := os.ReadFile(f.Name())
+	if err != nil {
+		return errs.WithPrefix("failed reading temporary file:", err)
+	}
+
+	return applyEdit(req, string(raw))
+}
+
+func formatValue(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprintf("%q", fmt.Sprint(v))
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,96 @@
+package debugger
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+func TestFormatRequest(t *testing.T) {
+	req := &goatfile.Request{
+		Method: "POST",
+		URI:    "https://example.com/api/users",
+		Header: http.Header{
+			"Content-Type":  {"application/json"},
+			"Authorization": {"Bearer token"},
+		},
+		Body: goatfile.StringContent("{\"name\": \"<goat>\"}\n"),
+	}
+	req.QueryParams = map[string]any{"page": 2, "fields": []any{"id", "name"}}
+
+	assert.Equal(t,
+		"POST https://example.com/api/users\n"+
+			"\n[QueryParams]\n"+
+			"fields = [\"id\",\"name\"]\n"+
+			"page = 2\n"+
+			"\n[Header]\n"+
+			"Authorization: Bearer token\n"+
+			"Content-Type: application/json\n"+
+			"\n[Body]\n```\n{\"name\": \"<goat>\"}\n```\n",
+		formatRequest(req))
+
+	req.Body = goatfile.FileContent{}
+	assert.Contains(t, formatRequest(req), "// The body of this request can not be edited.")
+}
+
+func TestApplyEdit(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		req := &goatfile.Request{
+			Method: "POST",
+			URI:    "https://example.com/api/users",
+			Header: http.Header{"Content-Type": {"application/json"}},
+			Body:   goatfile.StringContent("{\"name\": \"goat\"}\n"),
+		}
+		req.QueryParams = map[string]any{"page": int64(2)}
+
+		edited := *req
+		err := applyEdit(&edited, formatRequest(req))
+		assert.Nil(t, err)
+		assert.Equal(t, req.Method, edited.Method)
+		assert.Equal(t, req.URI, edited.URI)
+		assert.Equal(t, req.Header, edited.Header)
+		assert.Equal(t, req.QueryParams, edited.QueryParams)
+		assert.Equal(t, req.Body, edited.Body)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		req := &goatfile.Request{
+			Method: "POST",
+			URI:    "https://example.com/api/users",
+			Body:   goatfile.StringContent(`{"name": "goat"}`),
+		}
+
+		err := applyEdit(req, "PUT https://example.com/api/users/1\n\n[Header]\nX-Foo: bar\n")
+		assert.Nil(t, err)
+		assert.Equal(t, "PUT", req.Method)
+		assert.Equal(t, "https://example.com/api/users/1", req.URI)
+		assert.Equal(t, http.Header{"X-Foo": {"bar"}}, req.Header)
+		assert.Equal(t, goatfile.NoContent{}, req.Body)
+	})
+
+	t.Run("not-editable-body", func(t *testing.T) {
+		req := &goatfile.Request{
+			Method: "POST",
+			URI:    "https://example.com/api/upload",
+			Body:   goatfile.FileContent{},
+		}
+
+		err := applyEdit(req, formatRequest(req))
+		assert.Nil(t, err)
+		assert.Equal(t, goatfile.FileContent{}, req.Body)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		req := &goatfile.Request{Method: "GET", URI: "https://example.com"}
+
+		err := applyEdit(req, "GET https://example.com/a\n\n---\n\nGET https://example.com/b\n")
+		assert.ErrorIs(t, err, ErrInvalidEdit)
+
+		err = applyEdit(req, "")
+		assert.ErrorIs(t, err, ErrInvalidEdit)
+
+		assert.Equal(t, "https://example.com", req.URI)
+	})
+}
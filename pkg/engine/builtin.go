@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// builtinNames holds the names of the values registered
+// as builtins of an engine. Builtins are excluded from the
+// state of the engine and are not overwritten by SetState.
+type builtinNames map[string]struct{}
+
+func (t builtinNames) add(name string) {
+	t[name] = struct{}{}
+}
+
+func (t builtinNames) has(name string) bool {
+	_, ok := t[name]
+	return ok
+}
+
+// excluded returns true if the value with the given name
+// and type must not be part of the state of an engine.
+// This is the case for builtins, <null> values and
+// functions.
+func (t builtinNames) excluded(name string, typ reflect.Type) bool {
+	return t.has(name) || typ == nil || typ.Kind() == reflect.Func
+}
+
+// assertMessage returns the message of a failed
+// assertion with the given optional message parts.
+func assertMessage(msg []string) string {
+	mesg := "assertion failed"
+	if len(msg) != 0 {
+		mesg = fmt.Sprintf("%s: %s", mesg, strings.Join(msg, " "))
+	}
+	return mesg
+}
+
+// assertEqMessage returns the message of a failed
+// equality assertion of value and expected.
+func assertEqMessage(value, expected any, msg []string) string {
+	part := "unexpected value"
+	if len(msg) != 0 {
+		part = strings.Join(msg, " ")
+	}
+
+	return fmt.Sprintf("assertion failed: %s: expected `%v` != received `%v`", part, expected, value)
+}
+
+// jq runs the given jq query on object
+// and returns all results.
+func jq(object any, src string) ([]any, error) {
+	query, err := gojq.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("command parsing failed: %s", err.Error())
+	}
+
+	var results []any
+	iter := query.Run(object)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			if err, ok := err.(*gojq.HaltError); ok && err.Value() == nil {
+				break
+			}
+			return nil, err
+		}
+		results = append(results, v)
+	}
+
+	return results, nil
+}
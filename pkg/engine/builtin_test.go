@@ -0,0 +1,22 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltins_State(t *testing.T) {
+	for name, newEngine := range map[string]func() Engine{"expr": NewExpr, "goja": NewGoja} {
+		eng := newEngine()
+		require.Nil(t, eng.SetBuiltin("config", map[string]any{"name": "goat"}), name)
+
+		eng.SetState(State{"config": "overwritten", "a": int64(1)})
+
+		v, err := eng.Eval(`config.name`)
+		require.Nil(t, err, name)
+		assert.Equal(t, "goat", v, name)
+		assert.Equal(t, State{"a": int64(1)}, eng.State(), name)
+	}
+}
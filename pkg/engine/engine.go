@@ -10,12 +10,45 @@ type Engine interface {
 	// name to the global context of the runtime.
 	Set(name string, v any) error
 
+	// SetBuiltin sets the given value by the given
+	// name to the global context of the runtime like
+	// Set. Builtins are excluded from the State and
+	// are not overwritten by SetState.
+	SetBuiltin(name string, v any) error
+
+	// Unset removes the value by the given
+	// name from the global context of the
+	// runtime.
+	Unset(name string) error
+
 	// Run executes the given script in the
 	// runtime.
 	Run(script string) error
 
+	// Eval evaluates the given expression in
+	// the runtime and returns the result value.
+	Eval(expr string) (any, error)
+
 	// State returns a map of all set
 	// variables in the global state
-	// which are not of the type 'function'.
+	// which are not of the type 'function'
+	// and have not been set as builtins.
 	State() State
 }
+
+// FunctionLoader is implemented by engines which
+// can provide functions defined in scripts to be
+// used in templates.
+type FunctionLoader interface {
+	// LoadFunctions executes the given script as
+	// module and returns the functions it exports.
+	LoadFunctions(script string) (map[string]any, error)
+}
+
+// Transpiler is implemented by engines which can
+// transpile scripts before executing them.
+type Transpiler interface {
+	// SetTranspile enables or disables transpiling
+	// scripts before they are executed.
+	SetTranspile(enabled bool)
+}
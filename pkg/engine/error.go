@@ -1,6 +1,12 @@
 package engine
 
-import "github.com/studio-b12/goat/pkg/errs"
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/dop251/goja"
+	"github.com/studio-b12/goat/pkg/errs"
+)
 
 // Exception wraps an engine execution error
 // and holds a simple message concluding the
@@ -9,6 +15,11 @@ type Exception struct {
 	errs.InnerError
 
 	Msg string
+
+	// line and column are set by engines which
+	// determine the position of exceptions
+	// themselves.
+	line, column int
 }
 
 func (t Exception) Error() string {
@@ -17,3 +28,55 @@ func (t Exception) Error() string {
 	}
 	return t.Msg
 }
+
+// Position returns the line and column in the executed
+// script at which the exception has been thrown. ok is
+// false if the position can not be determined.
+func (t Exception) Position() (line, column int, ok bool) {
+	if t.line > 0 {
+		return t.line, t.column, true
+	}
+
+	gojaEx, isGoja := t.Inner.(*goja.Exception)
+	if !isGoja {
+		return 0, 0, false
+	}
+
+	// Skip native frames like the ones of builtin
+	// functions and frames of required modules.
+	for _, frame := range gojaEx.Stack() {
+		pos := frame.Position()
+		if pos.Line > 0 && frame.SrcName() == "" {
+			return pos.Line, pos.Column, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// syntaxErrorPosition matches the position in the
+// message of syntax errors thrown by the runtime.
+var syntaxErrorPosition = regexp.MustCompile(`^SyntaxError: .*?Line (\d+):(\d+) `)
+
+// ErrorPosition returns the line and column in the executed
+// script at which the given error occurred. This includes
+// thrown exceptions as well as syntax errors. ok is false
+// if the position can not be determined.
+func ErrorPosition(err error) (line, column int, ok bool) {
+	ex, isEx := errs.As[Exception](err)
+	if !isEx {
+		return 0, 0, false
+	}
+
+	if line, column, ok = ex.Position(); ok {
+		return line, column, ok
+	}
+
+	match := syntaxErrorPosition.FindStringSubmatch(ex.Msg)
+	if match == nil {
+		return 0, 0, false
+	}
+	line, _ = strconv.Atoi(match[1])
+	column, _ = strconv.Atoi(match[2])
+	return line, column, line > 0
+}
@@ -0,0 +1,301 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/file"
+	"github.com/expr-lang/expr/vm/runtime"
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/stdlib"
+	"github.com/zekrotja/rogu"
+	"github.com/zekrotja/rogu/log"
+)
+
+// Expr is a lightweight Engine implementation
+// evaluating expressions of the expr language
+// (https://expr-lang.org) instead of JavaScript.
+//
+// A script consists of statements separated by line
+// breaks or semicolons. Each statement is either an
+// expression or an assignment of an expression to a
+// global variable (name = expression).
+type Expr struct {
+	values   map[string]any
+	builtins builtinNames
+}
+
+var _ Engine = (*Expr)(nil)
+
+// NewExpr initializes a new Expr engine
+// and sets builtin functions to the global
+// scope.
+func NewExpr() Engine {
+	var t Expr
+
+	t.values = map[string]any{}
+	t.builtins = builtinNames{}
+
+	t.SetBuiltin("assert", t.builtin_assert)
+	t.SetBuiltin("assert_eq", t.builtin_assert_eq)
+	t.SetBuiltin("debug", builtin_log(log.Debug))
+	t.SetBuiltin("debugf", builtin_logf(log.Debug))
+	t.SetBuiltin("info", builtin_log(log.Info))
+	t.SetBuiltin("infof", builtin_logf(log.Info))
+	t.SetBuiltin("warn", builtin_log(log.Warn))
+	t.SetBuiltin("warnf", builtin_logf(log.Warn))
+	t.SetBuiltin("error", builtin_log(log.Error))
+	t.SetBuiltin("errorf", builtin_logf(log.Error))
+	t.SetBuiltin("fatal", builtin_log(log.Fatal))
+	t.SetBuiltin("fatalf", builtin_logf(log.Fatal))
+	t.SetBuiltin("print", t.builtin_print)
+	t.SetBuiltin("printf", t.builtin_printf)
+	t.SetBuiltin("println", t.builtin_println)
+	t.SetBuiltin("jq", jq)
+
+	for name, fn := range stdlib.Funcs {
+		t.SetBuiltin(name, fn)
+	}
+
+	return &t
+}
+
+func (t *Expr) SetState(s State) {
+	for k, v := range s {
+		if t.builtins.has(k) {
+			continue
+		}
+		t.Set(k, v)
+	}
+}
+
+func (t *Expr) SetBuiltin(name string, v any) error {
+	t.builtins.add(name)
+	return t.Set(name, v)
+}
+
+func (t *Expr) Set(name string, v any) error {
+	t.values[name] = v
+	return nil
+}
+
+func (t *Expr) Unset(name string) error {
+	delete(t.values, name)
+	return nil
+}
+
+func (t *Expr) Run(script string) error {
+	_, err := t.Eval(script)
+	return err
+}
+
+func (t *Expr) Eval(script string) (v any, err error) {
+	for _, stmt := range splitExprStatements(script) {
+		program, err := expr.Compile(stmt.src, expr.Env(t.values))
+		if err != nil {
+			return nil, stmt.exception(err)
+		}
+
+		v, err = expr.Run(program, t.values)
+		if err != nil {
+			return nil, stmt.exception(err)
+		}
+
+		if stmt.name != "" {
+			t.values[stmt.name] = v
+		}
+	}
+
+	return v, nil
+}
+
+func (t *Expr) State() State {
+	values := make(State)
+	for key, v := range t.values {
+		if t.builtins.excluded(key, reflect.TypeOf(v)) {
+			continue
+		}
+		values[key] = v
+	}
+
+	return values
+}
+
+func (t *Expr) builtin_assert(v bool, msg ...any) (bool, error) {
+	if !v {
+		return false, errors.New(assertMessage(exprStrings(msg)))
+	}
+	return true, nil
+}
+
+func (t *Expr) builtin_assert_eq(value any, expected any, msg ...any) (bool, error) {
+	if !runtime.Equal(value, expected) {
+		return false, errors.New(assertEqMessage(value, expected, exprStrings(msg)))
+	}
+	return true, nil
+}
+
+// builtin_log returns a builtin logging the
+// given message parts with the given level.
+func builtin_log(level func() *rogu.Event) func(msg ...string) {
+	return func(msg ...string) {
+		level().Msg(strings.Join(msg, " "))
+	}
+}
+
+// builtin_logf returns a builtin logging the given
+// formatted message with the given level.
+func builtin_logf(level func() *rogu.Event) func(format string, v ...any) {
+	return func(format string, v ...any) {
+		level().Msgf(format, v...)
+	}
+}
+
+func (t *Expr) builtin_print(msg ...string) {
+	fmt.Print(strings.Join(msg, " "))
+}
+
+func (t *Expr) builtin_printf(format string, v ...any) {
+	fmt.Printf(format, v...)
+}
+
+func (t *Expr) builtin_println(msg ...string) {
+	fmt.Println(strings.Join(msg, " "))
+}
+
+// exprStrings returns the string representations
+// of the given values.
+func exprStrings(v []any) []string {
+	s := make([]string, len(v))
+	for i, e := range v {
+		s[i] = fmt.Sprint(e)
+	}
+	return s
+}
+
+// exprStatement is a single statement of a script
+// evaluated by the Expr engine.
+type exprStatement struct {
+	// src is the expression of the statement. The
+	// assignment part of the statement is replaced
+	// by spaces so that positions in src match the
+	// positions in the statement.
+	src string
+
+	// name is the name of the variable the result
+	// is assigned to or empty if the statement is
+	// not an assignment.
+	name string
+
+	// line and column are the position of the
+	// start of the statement in the script.
+	line, column int
+}
+
+// exprAssignment matches statements assigning an
+// expression to a global variable.
+var exprAssignment = regexp.MustCompile(`^\s*([A-Za-z_$][A-Za-z0-9_$]*)\s*=($|[^=])`)
+
+// exception wraps the given error returned when
+// compiling or running the statement into an
+// Exception with the position of the error in
+// the script.
+func (t exprStatement) exception(err error) error {
+	ex := Exception{InnerError: errs.InnerError{Inner: err}, Msg: err.Error()}
+
+	var fileErr *file.Error
+	if errors.As(err, &fileErr) {
+		ex.Msg = fileErr.Message
+		ex.line = t.line + fileErr.Line - 1
+		ex.column = fileErr.Column + 1
+		if fileErr.Line == 1 {
+			ex.column += t.column - 1
+		}
+	}
+
+	return ex
+}
+
+// splitExprStatements splits the given script into its
+// statements. Statements are separated by line breaks
+// and semicolons outside of parentheses, brackets,
+// braces and string literals. Comments starting with
+// // are removed.
+func splitExprStatements(script string) []exprStatement {
+	var (
+		stmts        []exprStatement
+		current      strings.Builder
+		depth        int
+		quote        rune
+		escaped      bool
+		comment      bool
+		line, column = 1, 0
+		start        = [2]int{1, 1}
+	)
+
+	flush := func() {
+		src := current.String()
+		current.Reset()
+		if strings.TrimSpace(src) == "" {
+			return
+		}
+
+		stmt := exprStatement{src: src, line: start[0], column: start[1]}
+		if m := exprAssignment.FindStringSubmatchIndex(src); m != nil {
+			stmt.name = src[m[2]:m[3]]
+			stmt.src = strings.Repeat(" ", m[5]-1) + src[m[5]-1:]
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	runes := []rune(script)
+	for i, r := range runes {
+		column++
+		if current.Len() == 0 {
+			start = [2]int{line, column}
+		}
+
+		switch {
+		case r == '\n':
+			comment = false
+		case comment:
+			r = ' '
+		case quote != 0:
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\' && quote != '`':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			comment = true
+			r = ' '
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		}
+
+		if depth <= 0 && quote == 0 && (r == '\n' || r == ';') {
+			flush()
+		} else {
+			current.WriteRune(r)
+		}
+
+		if r == '\n' {
+			line++
+			column = 0
+		}
+	}
+	flush()
+
+	return stmts
+}
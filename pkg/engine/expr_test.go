@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exprTestResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       any
+}
+
+func TestExpr_Eval(t *testing.T) {
+	eng := NewExpr()
+	eng.SetState(State{
+		"n":    int64(3),
+		"f":    1.5,
+		"name": "goat",
+		"body": map[string]any{"items": []any{1.0, 2.0}, "user": map[string]any{"id": 5.0}},
+		"response": exprTestResponse{
+			StatusCode: 201,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+		},
+	})
+
+	cases := []struct {
+		expr     string
+		expected any
+	}{
+		{`1 + 2 * 3`, 7},
+		{`(1 + 2) * 3`, 9},
+		{`7 / 2`, 3.5},
+		{`7 % 4`, 3},
+		{`-n + f`, -1.5},
+		{`"a" + string(n)`, "a3"},
+		{`'it\'s'`, "it's"},
+		{`n > 2 && n <= 3`, true},
+		{`!(n == 3)`, false},
+		{`nil ?? "default"`, "default"},
+		{`n > 5 ? "big" : "small"`, "small"},
+		{`"abc" < "abd"`, true},
+		{`body.items[1]`, 2.0},
+		{`body["user"].id == 5`, true},
+		{`body.missing`, nil},
+		{`body.missing?.id`, nil},
+		{`len(body.items)`, 2},
+		{`len(name)`, 4},
+		{`filter(body.items, # > 1)`, []any{2.0}},
+		{`response.StatusCode >= 200 && response.StatusCode < 300`, true},
+		{`response.Header.Get("content-type")`, "application/json"},
+		{`[1, "a", true]`, []any{1, "a", true}},
+		{`{a: 1, "b": [2]}`, map[string]any{"a": 1, "b": []any{2}}},
+		{`jq(body, ".items[0]")[0]`, 1.0},
+		{"x = 2\ny = x * (\n  n + 1\n)\ny", 8},
+		{`a = 1; b = a + 1`, 2},
+	}
+
+	for _, c := range cases {
+		v, err := eng.Eval(c.expr)
+		if assert.Nil(t, err, c.expr) {
+			assert.Equal(t, c.expected, v, c.expr)
+		}
+	}
+}
+
+func TestExpr_State(t *testing.T) {
+	eng := NewExpr()
+	eng.SetState(State{"a": int64(1)})
+
+	err := eng.Run(`
+// comments are ignored
+b = a + 1
+c = nil
+`)
+	require.Nil(t, err)
+
+	assert.Equal(t, State{"a": int64(1), "b": 2}, eng.State())
+}
+
+func TestExpr_Errors(t *testing.T) {
+	eng := NewExpr()
+	eng.Set("status", int64(404))
+
+	cases := []struct {
+		script string
+		msg    string
+		line   int
+		column int
+	}{
+		{"x = 1\nassert(status == 200, \"status was\", status)", "assertion failed: status was 404", 2, 1},
+		{"assert_eq(status, 200)", "assertion failed: unexpected value: expected `200` != received `404`", 1, 1},
+		{"\n  undefinedVar + 1", "unknown name undefinedVar", 2, 3},
+		{"status.foo.bar", "type int64 has no field foo", 1, 8},
+		{"x = (1 +", "unexpected token EOF", 1, 8},
+		{"status(1)", "int64 is not callable", 1, 1},
+		{"a = 1; b = a +* 2", "unexpected token Operator(\"*\")", 1, 15},
+	}
+
+	for _, c := range cases {
+		err := eng.Run(c.script)
+		require.NotNil(t, err, c.script)
+		assert.Equal(t, c.msg, err.Error(), c.script)
+
+		line, column, ok := ErrorPosition(err)
+		assert.True(t, ok, c.script)
+		assert.Equal(t, c.line, line, c.script)
+		assert.Equal(t, c.column, column, c.script)
+	}
+}
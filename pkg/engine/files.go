@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+var (
+	ErrFileAccessDenied = errors.New("access to files outside of the project root is not allowed")
+	ErrNoArtifactsDir   = errors.New("no artifacts directory has been specified")
+)
+
+// FileAccess configures the access of scripts
+// to the local file system.
+type FileAccess struct {
+	// Root is the project root directory files
+	// and modules are read from. If empty, the
+	// directory of the executed Goatfile is used.
+	Root string
+
+	// ArtifactsDir is the directory files are
+	// written to. If empty, writing files is not
+	// possible.
+	ArtifactsDir string
+
+	// Unrestricted allows reading and writing files
+	// outside of the Root and ArtifactsDir.
+	Unrestricted bool
+}
+
+// ReadPath resolves the given path relative to dir, which
+// is the directory of the executed Goatfile, and returns
+// it when it is located in the project root.
+func (t FileAccess) ReadPath(dir, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	return t.confine(t.root(dir), path)
+}
+
+// ModulePath returns the given absolute module path when
+// it is located in the project root or in libDir, if set.
+// dir is the directory of the executed Goatfile.
+func (t FileAccess) ModulePath(dir, libDir, path string) (string, error) {
+	if libDir != "" {
+		if libPath, err := t.confine(libDir, path); err == nil {
+			return libPath, nil
+		}
+	}
+
+	return t.confine(t.root(dir), path)
+}
+
+// WritePath resolves the given path relative to the
+// artifacts directory and returns it when it is located
+// in the artifacts directory.
+func (t FileAccess) WritePath(path string) (string, error) {
+	if t.ArtifactsDir == "" {
+		return "", ErrNoArtifactsDir
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.ArtifactsDir, path)
+	}
+
+	return t.confine(t.ArtifactsDir, path)
+}
+
+// root returns the project root or, if not set,
+// the given directory of the executed Goatfile.
+func (t FileAccess) root(dir string) string {
+	if t.Root != "" {
+		return t.Root
+	}
+	return dir
+}
+
+// confine returns the absolute path of path and checks,
+// unless unrestricted, that it is located in root. Symbolic
+// links are resolved so that they can not be used to escape
+// from root.
+func (t FileAccess) confine(root, path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	if t.Unrestricted {
+		return path, nil
+	}
+
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(evalSymlinks(root), evalSymlinks(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errs.WithSuffix(ErrFileAccessDenied, "("+path+")")
+	}
+
+	return path, nil
+}
+
+// evalSymlinks resolves symbolic links in the longest
+// existing prefix of the given absolute path.
+func evalSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+
+	return filepath.Join(evalSymlinks(parent), filepath.Base(path))
+}
+
+// ensureDir creates the parent directory of
+// the given file path, if it does not exist.
+func ensureDir(path string) error {
+	return os.MkdirAll(filepath.Dir(path), os.ModePerm)
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAccess_ReadPath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	require.Nil(t, os.Symlink(outside, filepath.Join(root, "link")))
+
+	files := FileAccess{Root: root}
+
+	path, err := files.ReadPath(filepath.Join(root, "tests"), "../fixtures/user.json")
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "fixtures", "user.json"), path)
+
+	_, err = files.ReadPath(filepath.Join(root, "tests"), "../../secret")
+	assert.ErrorIs(t, err, ErrFileAccessDenied)
+
+	_, err = files.ReadPath(root, filepath.Join(outside, "secret"))
+	assert.ErrorIs(t, err, ErrFileAccessDenied)
+
+	_, err = files.ReadPath(root, "link/secret")
+	assert.ErrorIs(t, err, ErrFileAccessDenied)
+
+	files.Unrestricted = true
+	path, err = files.ReadPath(root, filepath.Join(outside, "secret"))
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(outside, "secret"), path)
+}
+
+func TestFileAccess_DefaultRoot(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := FileAccess{}.ReadPath(filepath.Join(dir, "tests"), "fixtures/user.json")
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "tests", "fixtures", "user.json"), path)
+
+	_, err = FileAccess{}.ReadPath(filepath.Join(dir, "tests"), "../user.json")
+	assert.ErrorIs(t, err, ErrFileAccessDenied)
+}
+
+func TestFileAccess_ModulePath(t *testing.T) {
+	root := t.TempDir()
+	libDir := t.TempDir()
+	outside := t.TempDir()
+
+	files := FileAccess{Root: root}
+
+	path, err := files.ModulePath(root, libDir, filepath.Join(root, "lib", "helpers.js"))
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "lib", "helpers.js"), path)
+
+	path, err = files.ModulePath(root, libDir, filepath.Join(libDir, "status.js"))
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(libDir, "status.js"), path)
+
+	_, err = files.ModulePath(root, libDir, filepath.Join(outside, "secret.js"))
+	assert.ErrorIs(t, err, ErrFileAccessDenied)
+
+	_, err = files.ModulePath(root, "", filepath.Join(libDir, "status.js"))
+	assert.ErrorIs(t, err, ErrFileAccessDenied)
+
+	files.Unrestricted = true
+	path, err = files.ModulePath(root, "", filepath.Join(outside, "secret.js"))
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(outside, "secret.js"), path)
+}
+
+func TestFileAccess_WritePath(t *testing.T) {
+	artifacts := t.TempDir()
+
+	_, err := FileAccess{}.WritePath("out.json")
+	assert.ErrorIs(t, err, ErrNoArtifactsDir)
+
+	files := FileAccess{ArtifactsDir: artifacts}
+
+	path, err := files.WritePath("responses/out.json")
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(artifacts, "responses", "out.json"), path)
+
+	_, err = files.WritePath("../out.json")
+	assert.ErrorIs(t, err, ErrFileAccessDenied)
+}
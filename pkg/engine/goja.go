@@ -1,81 +1,184 @@
 package engine
 
 import (
-	"reflect"
-
 	"github.com/dop251/goja"
+	"github.com/studio-b12/goat/pkg/stdlib"
 )
 
 // Goja is the Engine implementation using
 // ECMAScript 5.
 type Goja struct {
 	rt *goja.Runtime
+
+	modules   *Modules
+	moduleDir string
+	files     FileAccess
+	loaded    map[string]*goja.Object
+	functions map[string]map[string]any
+	transpile bool
+	builtins  builtinNames
+	initErr   error
 }
 
 var _ Engine = (*Goja)(nil)
 
+var _ ModuleLoader = (*Goja)(nil)
+
+var _ Transpiler = (*Goja)(nil)
+
 // NewGoja initializes the Goja engine runtime
 // and sets builtin functions to the global scope.
 func NewGoja() Engine {
+	return NewGojaWithModules(NewModules())
+}
+
+// GojaOptions configures a Goja engine.
+type GojaOptions struct {
+	// Modules are used to resolve and load modules
+	// passed to require. If nil, a new instance of
+	// Modules is used.
+	Modules *Modules
+
+	// Files configures the access of scripts to
+	// the local file system.
+	Files FileAccess
+
+	// Transpile enables transpiling scripts using
+	// esbuild before they are executed.
+	Transpile bool
+}
+
+// NewGojaWithModules initializes the Goja engine runtime
+// like NewGoja using the given modules for require. All
+// modules of the script library of modules are preloaded
+// and their exports are set to the global scope.
+//
+// If preloading fails, the error is returned on the first
+// execution of a script.
+func NewGojaWithModules(modules *Modules) Engine {
+	return NewGojaWithOptions(GojaOptions{Modules: modules})
+}
+
+// NewGojaWithOptions initializes the Goja engine runtime
+// like NewGojaWithModules using the given options.
+func NewGojaWithOptions(opts GojaOptions) Engine {
 	var t Goja
 
+	if opts.Modules == nil {
+		opts.Modules = NewModules()
+	}
+
 	t.rt = goja.New()
+	t.rt.SetRandSource(stdlib.Float64)
+	t.rt.SetTimeSource(stdlib.Now)
+	t.modules = opts.Modules
+	t.files = opts.Files
+	t.transpile = opts.Transpile
+	t.loaded = map[string]*goja.Object{}
+	t.functions = map[string]map[string]any{}
+	t.builtins = builtinNames{}
 
-	t.Set("assert", t.builtin_assert)
-	t.Set("assert_eq", t.builtin_assert_eq)
-	t.Set("debug", t.builtin_debug)
-	t.Set("debugf", t.builtin_debugf)
-	t.Set("info", t.builtin_info)
-	t.Set("infof", t.builtin_infof)
-	t.Set("warn", t.builtin_warn)
-	t.Set("warnf", t.builtin_warnf)
-	t.Set("error", t.builtin_error)
-	t.Set("errorf", t.builtin_errorf)
-	t.Set("fatal", t.builtin_fatal)
-	t.Set("fatalf", t.builtin_fatalf)
-	t.Set("print", t.builtin_print)
-	t.Set("printf", t.builtin_printf)
-	t.Set("println", t.builtin_println)
-	t.Set("jq", t.builtin_jq)
+	t.SetBuiltin("assert", t.builtin_assert)
+	t.SetBuiltin("assert_eq", t.builtin_assert_eq)
+	t.SetBuiltin("debug", t.builtin_debug)
+	t.SetBuiltin("debugf", t.builtin_debugf)
+	t.SetBuiltin("info", t.builtin_info)
+	t.SetBuiltin("infof", t.builtin_infof)
+	t.SetBuiltin("warn", t.builtin_warn)
+	t.SetBuiltin("warnf", t.builtin_warnf)
+	t.SetBuiltin("error", t.builtin_error)
+	t.SetBuiltin("errorf", t.builtin_errorf)
+	t.SetBuiltin("fatal", t.builtin_fatal)
+	t.SetBuiltin("fatalf", t.builtin_fatalf)
+	t.SetBuiltin("print", t.builtin_print)
+	t.SetBuiltin("printf", t.builtin_printf)
+	t.SetBuiltin("println", t.builtin_println)
+	t.SetBuiltin("jq", t.builtin_jq)
+	t.SetBuiltin("require", t.builtin_require)
+	t.SetBuiltin("readFile", t.builtin_readFile)
+	t.SetBuiltin("readJSON", t.builtin_readJSON)
+	t.SetBuiltin("writeFile", t.builtin_writeFile)
+	t.SetBuiltin("env", t.builtin_env)
+
+	for name, fn := range stdlib.Funcs {
+		t.SetBuiltin(name, fn)
+	}
+
+	t.initErr = t.preload()
 
 	return &t
 }
 
 func (t *Goja) SetState(s State) {
 	for k, v := range s {
+		if t.builtins.has(k) {
+			continue
+		}
 		t.Set(k, v)
 	}
 }
 
+func (t *Goja) SetBuiltin(name string, v any) error {
+	t.builtins.add(name)
+	return t.Set(name, v)
+}
+
 func (t *Goja) Set(name string, v any) error {
 	return t.rt.Set(name, v)
 }
 
+func (t *Goja) Unset(name string) error {
+	return t.rt.GlobalObject().Delete(name)
+}
+
 func (t *Goja) Run(script string) error {
-	_, err := t.rt.RunString(script)
-	if gojaException, ok := err.(*goja.Exception); ok {
-		// Extract Goja Exceptions into a new exception
-		// wrapper so that we can handle how error
-		// messages are printed.
-		var ex Exception
-		ex.Inner = gojaException
-		val := gojaException.Value()
-		if val != nil {
-			ex.Msg = val.String()
-		}
-		return ex
+	if t.initErr != nil {
+		return t.initErr
 	}
-	return err
+	script, err := t.prepare(script)
+	if err != nil {
+		return err
+	}
+	_, err = t.rt.RunString(script)
+	return wrapException(err)
+}
+
+func (t *Goja) Eval(expr string) (any, error) {
+	if t.initErr != nil {
+		return nil, t.initErr
+	}
+	expr, err := t.prepare(expr)
+	if err != nil {
+		return nil, err
+	}
+	v, err := t.rt.RunString(expr)
+	if err != nil {
+		return nil, wrapException(err)
+	}
+	return v.Export(), nil
+}
+
+// SetTranspile enables or disables transpiling
+// scripts before they are executed.
+func (t *Goja) SetTranspile(enabled bool) {
+	t.transpile = enabled
+}
+
+// prepare returns the given script transpiled, if
+// transpiling is enabled. Otherwise, the script is
+// returned unchanged.
+func (t *Goja) prepare(script string) (string, error) {
+	if !t.transpile {
+		return script, nil
+	}
+	return transpile(script)
 }
 
 func (t *Goja) State() State {
 	values := make(State)
 	for _, key := range t.rt.GlobalObject().Keys() {
 		v := t.rt.Get(key)
-		typ := v.ExportType()
-		// Don't extract <null> values or function
-		// type instances.
-		if typ == nil || typ.Kind() == reflect.Func {
+		if t.builtins.excluded(key, v.ExportType()) {
 			continue
 		}
 		values[key] = v.Export()
@@ -83,3 +186,19 @@ func (t *Goja) State() State {
 
 	return values
 }
+
+func wrapException(err error) error {
+	if gojaException, ok := err.(*goja.Exception); ok {
+		// Extract Goja Exceptions into a new exception
+		// wrapper so that we can handle how error
+		// messages are printed.
+		var ex Exception
+		ex.Inner = gojaException
+		val := gojaException.Value()
+		if val != nil {
+			ex.Msg = val.String()
+		}
+		return ex
+	}
+	return err
+}
@@ -2,7 +2,6 @@ package engine
 
 import (
 	"fmt"
-	"github.com/itchyny/gojq"
 	"reflect"
 	"strings"
 
@@ -14,12 +13,7 @@ func (t *Goja) builtin_assert(v bool, msg ...string) {
 		return
 	}
 
-	mesg := "assertion failed"
-	if len(msg) != 0 {
-		mesg = fmt.Sprintf("%s: %s", mesg, strings.Join(msg, " "))
-	}
-
-	panic(t.rt.ToValue(mesg))
+	panic(t.rt.ToValue(assertMessage(msg)))
 }
 
 func (t *Goja) builtin_assert_eq(value any, expected any, msg ...string) {
@@ -27,14 +21,7 @@ func (t *Goja) builtin_assert_eq(value any, expected any, msg ...string) {
 		return
 	}
 
-	part := "unexpected value"
-	if len(msg) != 0 {
-		part = strings.Join(msg, " ")
-	}
-
-	mesg := fmt.Sprintf("assertion failed: %s: expected `%v` != received `%v`", part, expected, value)
-
-	panic(t.rt.ToValue(mesg))
+	panic(t.rt.ToValue(assertEqMessage(value, expected, msg)))
 }
 
 func (t *Goja) builtin_debug(msg ...string) {
@@ -90,26 +77,9 @@ func (t *Goja) builtin_printf(format string, v ...any) {
 }
 
 func (t *Goja) builtin_jq(object any, src string) []any {
-	query, err := gojq.Parse(src)
+	results, err := jq(object, src)
 	if err != nil {
-		panic(t.rt.ToValue(fmt.Sprintf("command parsing failed: %s", err.Error())))
-	}
-
-	var results []any
-	iter := query.Run(object)
-	for {
-		v, ok := iter.Next()
-		if !ok {
-			break
-		}
-		if err, ok := v.(error); ok {
-			if err, ok := err.(*gojq.HaltError); ok && err.Value() == nil {
-				break
-			}
-			panic(t.rt.ToValue(err.Error()))
-		}
-		results = append(results, v)
+		panic(t.rt.ToValue(err.Error()))
 	}
-
 	return results
 }
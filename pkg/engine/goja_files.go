@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+func (t *Goja) builtin_readFile(path string) (string, error) {
+	path, err := t.files.ReadPath(t.moduleDir, path)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (t *Goja) builtin_readJSON(path string) (any, error) {
+	data, err := t.builtin_readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	err = json.Unmarshal([]byte(data), &v)
+	if err != nil {
+		return nil, errs.WithPrefix(fmt.Sprintf("failed parsing %s:", path), err)
+	}
+
+	return v, nil
+}
+
+func (t *Goja) builtin_writeFile(path string, data any) (string, error) {
+	path, err := t.files.WritePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	var content []byte
+	switch dt := data.(type) {
+	case string:
+		content = []byte(dt)
+	case []byte:
+		content = dt
+	default:
+		content, err = json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return "", errs.WithPrefix("failed encoding data:", err)
+		}
+	}
+
+	if err = ensureDir(path); err != nil {
+		return "", err
+	}
+
+	if err = os.WriteFile(path, content, 0o644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (t *Goja) builtin_env(name string) any {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	return v
+}
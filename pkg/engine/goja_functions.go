@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"github.com/dop251/goja"
+)
+
+var _ FunctionLoader = (*Goja)(nil)
+
+// LoadFunctions executes the given script like a module
+// and returns its exported functions. The functions can
+// be called from templates and are executed in the runtime
+// of the engine.
+//
+// Each distinct script is only executed once per engine.
+func (t *Goja) LoadFunctions(script string) (map[string]any, error) {
+	if funcs, ok := t.functions[script]; ok {
+		return funcs, nil
+	}
+
+	exports, err := t.runFunctions(script)
+	if err != nil {
+		return nil, functionsException(err)
+	}
+
+	funcs := map[string]any{}
+	if obj, ok := exports.(*goja.Object); ok {
+		for _, key := range obj.Keys() {
+			if fn, ok := goja.AssertFunction(obj.Get(key)); ok {
+				funcs[key] = t.templateFunc(fn)
+			}
+		}
+	}
+
+	t.functions[script] = funcs
+	return funcs, nil
+}
+
+func (t *Goja) runFunctions(script string) (goja.Value, error) {
+	// The wrapper is placed on its own line so that the
+	// positions of errors only need to be shifted by one
+	// line to match the script. It is transpiled together
+	// with the script so that the source map covers both.
+	script, err := t.prepare("(function(exports, require, module) {\n" + script + "\n})")
+	if err != nil {
+		return nil, err
+	}
+	wrapper, err := t.rt.RunString(script)
+	if err != nil {
+		return nil, err
+	}
+	fn, _ := goja.AssertFunction(wrapper)
+
+	module := t.rt.NewObject()
+	exports := t.rt.NewObject()
+	module.Set("exports", exports)
+
+	_, err = fn(goja.Undefined(), exports, t.rt.Get("require"), module)
+	if err != nil {
+		return nil, err
+	}
+
+	return module.Get("exports"), nil
+}
+
+// templateFunc wraps the given function so that it can
+// be called from templates.
+func (t *Goja) templateFunc(fn goja.Callable) func(args ...any) (any, error) {
+	return func(args ...any) (any, error) {
+		jsArgs := make([]goja.Value, 0, len(args))
+		for _, arg := range args {
+			jsArgs = append(jsArgs, t.rt.ToValue(arg))
+		}
+
+		v, err := fn(goja.Undefined(), jsArgs...)
+		if err != nil {
+			return nil, wrapException(err)
+		}
+
+		return v.Export(), nil
+	}
+}
+
+// functionsException wraps the given error occurred while
+// loading functions and corrects its position by the line
+// of the module wrapper.
+func functionsException(err error) error {
+	ex, ok := wrapException(err).(Exception)
+	if !ok {
+		return err
+	}
+
+	if line, column, ok := ErrorPosition(ex); ok && line > 1 {
+		ex.line, ex.column = line-1, column
+	}
+
+	return ex
+}
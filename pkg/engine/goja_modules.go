@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dop251/goja"
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+func (t *Goja) SetModuleDir(dir string) {
+	t.moduleDir = dir
+}
+
+func (t *Goja) builtin_require(name string) goja.Value {
+	return t.requireFrom(name, t.moduleDir)
+}
+
+// requireFrom loads the module with the given name required
+// from a script or module in dir and returns its exports.
+// Like files, modules can only be loaded from the project
+// root or the script library. Errors are thrown as exceptions
+// in the runtime.
+func (t *Goja) requireFrom(name, dir string) goja.Value {
+	path, err := t.modules.Resolve(name, dir)
+	if err == nil {
+		path, err = t.files.ModulePath(t.moduleDir, t.modules.LibDir(), path)
+	}
+	if err != nil {
+		panic(t.rt.ToValue(err.Error()))
+	}
+
+	exports, err := t.loadModule(path)
+	if err != nil {
+		if ex, ok := err.(*goja.Exception); ok {
+			panic(ex)
+		}
+		panic(t.rt.ToValue(fmt.Sprintf("failed loading module %s: %s", name, err.Error())))
+	}
+
+	return exports
+}
+
+// loadModule executes the module at the given path and
+// returns its exports. Each module is only executed once
+// per engine.
+func (t *Goja) loadModule(path string) (goja.Value, error) {
+	if module, ok := t.loaded[path]; ok {
+		return module.Get("exports"), nil
+	}
+
+	module := t.rt.NewObject()
+	exports := t.rt.NewObject()
+	module.Set("exports", exports)
+
+	if filepath.Ext(path) == ".json" {
+		src, err := t.modules.jsonSource(path)
+		if err != nil {
+			return nil, err
+		}
+		parse, _ := goja.AssertFunction(t.rt.Get("JSON").ToObject(t.rt).Get("parse"))
+		v, err := parse(goja.Undefined(), t.rt.ToValue(src))
+		if err != nil {
+			return nil, err
+		}
+		module.Set("exports", v)
+		t.loaded[path] = module
+		return v, nil
+	}
+
+	prog, err := t.modules.program(path)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapper, err := t.rt.RunProgram(prog)
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := goja.AssertFunction(wrapper)
+	if !ok {
+		return nil, fmt.Errorf("invalid module wrapper: %s", path)
+	}
+
+	// The module is registered before execution so
+	// that cyclic requires receive the exports set
+	// up to this point.
+	t.loaded[path] = module
+
+	dir := filepath.Dir(path)
+	require := func(name string) goja.Value {
+		return t.requireFrom(name, dir)
+	}
+
+	_, err = fn(goja.Undefined(),
+		exports, t.rt.ToValue(require), module, t.rt.ToValue(path), t.rt.ToValue(dir))
+	if err != nil {
+		delete(t.loaded, path)
+		return nil, err
+	}
+
+	return module.Get("exports"), nil
+}
+
+// preload loads all modules of the script library and
+// sets their exports to the global scope as builtins,
+// so that they are not part of the state.
+func (t *Goja) preload() error {
+	for _, path := range t.modules.Preload() {
+		exports, err := t.loadModule(path)
+		if err != nil {
+			return errs.WithPrefix(fmt.Sprintf("failed preloading module %s:", path), wrapException(err))
+		}
+
+		obj, ok := exports.(*goja.Object)
+		if !ok {
+			continue
+		}
+		for _, key := range obj.Keys() {
+			t.SetBuiltin(key, obj.Get(key))
+		}
+	}
+
+	return nil
+}
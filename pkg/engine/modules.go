@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja"
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+var (
+	ErrModuleNotFound    = errors.New("module not found")
+	ErrInvalidLibraryDir = errors.New("script library is not a directory")
+)
+
+// moduleExtension is the file extension tried when
+// a required module path has no extension.
+const moduleExtension = ".js"
+
+// ModuleLoader is implemented by engines which
+// support loading modules via require.
+type ModuleLoader interface {
+	// SetModuleDir sets the directory relative to
+	// which modules are resolved when required
+	// from scripts.
+	SetModuleDir(dir string)
+}
+
+// Modules reads and compiles CommonJS modules. Compiled
+// modules are cached, so that each module is only read
+// and compiled once when Modules is shared between all
+// engines of a run.
+type Modules struct {
+	mtx      sync.Mutex
+	programs map[string]*goja.Program
+	json     map[string]string
+
+	libDir  string
+	preload []string
+}
+
+// NewModules returns a new empty Modules instance.
+func NewModules() *Modules {
+	return &Modules{
+		programs: map[string]*goja.Program{},
+		json:     map[string]string{},
+	}
+}
+
+// SetLibDir sets the given directory as script library.
+// All modules in it are preloaded into each engine and
+// modules required by name without a relative path are
+// resolved from it.
+func (t *Modules) SetLibDir(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return errs.WithPrefix("failed reading script library:", err)
+	}
+	if !stat.IsDir() {
+		return ErrInvalidLibraryDir
+	}
+
+	var preload []string
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == moduleExtension {
+			preload = append(preload, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return errs.WithPrefix("failed reading script library:", err)
+	}
+	sort.Strings(preload)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.libDir = dir
+	t.preload = preload
+
+	return nil
+}
+
+// LibDir returns the script library directory
+// or an empty string if none has been set.
+func (t *Modules) LibDir() string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.libDir
+}
+
+// Preload returns the paths of all modules which
+// shall be preloaded into each engine.
+func (t *Modules) Preload() []string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.preload
+}
+
+// Resolve returns the path of the module with the given
+// name required from a script or module in dir.
+//
+// Names starting with './', '../' or '/' are resolved
+// relative to dir. All other names are resolved relative
+// to the script library directory. If the resolved path
+// does not exist, the extension '.js' is appended and,
+// if it is a directory, 'index.js' is tried.
+func (t *Modules) Resolve(name, dir string) (string, error) {
+	var path string
+
+	switch {
+	case filepath.IsAbs(name):
+		path = name
+	case name == "." || name == ".." ||
+		strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../"):
+		path = filepath.Join(dir, name)
+	default:
+		libDir := t.LibDir()
+		if libDir == "" {
+			return "", errs.WithSuffix(ErrModuleNotFound, fmt.Sprintf("(%s)", name))
+		}
+		path = filepath.Join(libDir, name)
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := []string{
+		path,
+		path + moduleExtension,
+		filepath.Join(path, "index"+moduleExtension),
+	}
+	for _, candidate := range candidates {
+		if stat, err := os.Stat(candidate); err == nil && !stat.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", errs.WithSuffix(ErrModuleNotFound, fmt.Sprintf("(%s)", name))
+}
+
+// program returns the compiled module at the given path.
+// The module source is wrapped into a function taking the
+// parameters exports, require, module, __filename and
+// __dirname.
+func (t *Modules) program(path string) (*goja.Program, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if prog, ok := t.programs[path]; ok {
+		return prog, nil
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The wrapper is prepended to the first line so that
+	// line numbers in errors match the module file.
+	wrapped := "(function(exports, require, module, __filename, __dirname) {" +
+		string(src) + "\n})"
+
+	prog, err := goja.Compile(path, wrapped, false)
+	if err != nil {
+		return nil, err
+	}
+
+	t.programs[path] = prog
+	return prog, nil
+}
+
+// jsonSource returns the content of the JSON
+// module at the given path.
+func (t *Modules) jsonSource(path string) (string, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if src, ok := t.json[path]; ok {
+		return src, nil
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	t.json[path] = string(src)
+	return string(src), nil
+}
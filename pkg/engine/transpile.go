@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/evanw/esbuild/pkg/api"
+)
+
+// transpile transforms the given script, which may contain
+// modern JavaScript or TypeScript syntax, into JavaScript
+// which can be executed by the runtime. An inline source map
+// is appended so that positions of exceptions map back to
+// the original script.
+func transpile(script string) (string, error) {
+	res := api.Transform(script, api.TransformOptions{
+		Loader:    api.LoaderTS,
+		Target:    api.ES2017,
+		Sourcemap: api.SourceMapInline,
+	})
+
+	if len(res.Errors) != 0 {
+		msg := res.Errors[0]
+		var ex Exception
+		ex.Inner = errors.New(msg.Text)
+		ex.Msg = msg.Text
+		if msg.Location != nil {
+			ex.line = msg.Location.Line
+			ex.column = msg.Location.Column + 1
+			ex.Msg = fmt.Sprintf("SyntaxError: Line %d:%d %s",
+				ex.line, ex.column, msg.Text)
+		}
+		return "", ex
+	}
+
+	return string(res.Code), nil
+}
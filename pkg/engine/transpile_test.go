@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoja_Transpile(t *testing.T) {
+	t.Run("typescript", func(t *testing.T) {
+		eng := NewGojaWithOptions(GojaOptions{Transpile: true})
+
+		err := eng.Run(`
+interface User { name: string; tags?: string[] }
+const user: User = { name: "goat" };
+var result: string = user.tags?.[0] ?? user.name.toUpperCase();
+`)
+		require.Nil(t, err)
+		assert.Equal(t, "GOAT", eng.State()["result"])
+
+		v, err := eng.Eval(`(result as string).length`)
+		require.Nil(t, err)
+		assert.EqualValues(t, 4, v)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		eng := NewGoja()
+
+		err := eng.Run(`const n: number = 1;`)
+		require.NotNil(t, err)
+	})
+
+	t.Run("exception-position", func(t *testing.T) {
+		eng := NewGojaWithOptions(GojaOptions{Transpile: true})
+
+		err := eng.Run("type N = number;\n\nconst n: N = 1;\n  throw new Error(`failed ${n}`);\n")
+		require.NotNil(t, err)
+		assert.Equal(t, "Error: failed 1", err.Error())
+
+		line, _, ok := ErrorPosition(err)
+		require.True(t, ok)
+		assert.Equal(t, 4, line)
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		eng := NewGojaWithOptions(GojaOptions{Transpile: true}).(*Goja)
+
+		funcs, err := eng.LoadFunctions(`
+type Named = { name?: string };
+exports.greet = (user: Named) => ` + "`hello ${user.name ?? \"goat\"}`" + `;
+`)
+		require.Nil(t, err)
+		greet := funcs["greet"].(func(args ...any) (any, error))
+		v, err := greet(map[string]any{})
+		require.Nil(t, err)
+		assert.Equal(t, "hello goat", v)
+
+		_, err = eng.LoadFunctions("exports.a = (n: number) => n;\nexports.b = ) => 2;\n")
+		require.NotNil(t, err)
+		line, _, ok := ErrorPosition(err)
+		require.True(t, ok)
+		assert.Equal(t, 2, line)
+
+		_, err = eng.LoadFunctions("const n: number = 1;\n\nthrow new Error(`failed ${n}`);\n")
+		require.NotNil(t, err)
+		line, _, ok = ErrorPosition(err)
+		require.True(t, ok)
+		assert.Equal(t, 3, line)
+	})
+
+	t.Run("syntax-error-position", func(t *testing.T) {
+		eng := NewGojaWithOptions(GojaOptions{Transpile: true})
+
+		err := eng.Run("const a = 1;\nconst b = ;\n")
+		require.NotNil(t, err)
+
+		line, column, ok := ErrorPosition(err)
+		require.True(t, ok)
+		assert.Equal(t, 2, line)
+		assert.Equal(t, 11, column)
+	})
+}
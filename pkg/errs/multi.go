@@ -33,6 +33,12 @@ func (t Errors) Error() string {
 	return sb.String()
 }
 
+// Unwrap returns the contained errors so that
+// errors.Is and errors.As can match any of them.
+func (t Errors) Unwrap() []error {
+	return t
+}
+
 // HasSome returns true if the inner error
 // array is not empty.
 func (t Errors) HasSome() bool {
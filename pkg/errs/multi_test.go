@@ -88,3 +88,22 @@ func TestJoin(t *testing.T) {
 		assert.ElementsMatch(t, joined, append(err1, err2...))
 	})
 }
+
+func TestErrors_Unwrap(t *testing.T) {
+	target := &testError{msg: "target"}
+	err := Errors{errors.New("err 1"), WithPrefix("prefix:", target)}
+
+	assert.ErrorIs(t, err, target)
+
+	found, ok := As[*testError](err)
+	assert.True(t, ok)
+	assert.Same(t, target, found)
+}
+
+type testError struct {
+	msg string
+}
+
+func (t *testError) Error() string {
+	return t.msg
+}
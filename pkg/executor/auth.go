@@ -0,0 +1,519 @@
+package executor
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/requester"
+	"github.com/zekrotja/rogu/log"
+)
+
+var (
+	ErrNoTokenURL        = errors.New("no tokenurl has been specified")
+	ErrInvalidGrant      = errors.New("invalid oauth2 grant type")
+	ErrNoAccessToken     = errors.New("token response contains no access_token")
+	ErrInvalidApiKeyIn   = errors.New("apikey can only be passed in 'header' or 'query'")
+	ErrUnsupportedDigest = errors.New("unsupported digest challenge")
+)
+
+// tokenExpiryLeeway is subtracted from the lifetime of
+// acquired OAuth2 tokens so that they are refreshed
+// before they actually expire.
+const tokenExpiryLeeway = 10 * time.Second
+
+// authenticator applies the authentication specified in
+// the [Auth] block of a request. Acquired OAuth2 tokens
+// and digest challenges are cached per client so that
+// they can be reused across requests and batches.
+type authenticator struct {
+	ctx context.Context
+	req requester.Requester
+
+	mtx        sync.Mutex
+	tokens     map[string]*oauth2Token
+	tokenLocks map[string]*sync.Mutex
+	digests    map[string]*digestChallenge
+}
+
+// newAuthenticator returns a new authenticator performing
+// token requests with the values of ctx. Like teardown steps,
+// token requests are not affected by the cancellation of ctx.
+func newAuthenticator(ctx context.Context, req requester.Requester) *authenticator {
+	return &authenticator{
+		ctx:        context.WithoutCancel(ctx),
+		req:        req,
+		tokens:     make(map[string]*oauth2Token),
+		tokenLocks: make(map[string]*sync.Mutex),
+		digests:    make(map[string]*digestChallenge),
+	}
+}
+
+// Do builds a request using newReq, applies the authentication
+// specified in opt and performs the request using do. OAuth2
+// tokens are requested using the given transport options.
+//
+// When digest authentication is used and the server responds
+// with a digest challenge, the request is built again and
+// re-sent with the answered challenge.
+func (t *authenticator) Do(
+	opt AuthOptions,
+	transport requester.TransportOptions,
+	newReq func() (*http.Request, error),
+	do func(*http.Request) (*http.Response, error),
+) (*http.Response, error) {
+	req, err := newReq()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = t.apply(req, opt, transport); err != nil {
+		return nil, err
+	}
+
+	res, err := do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.AuthType() != AuthTypeDigest || res.StatusCode != http.StatusUnauthorized {
+		return res, nil
+	}
+
+	challenge, err := parseDigestChallenge(res.Header.Values("WWW-Authenticate"))
+	if err != nil {
+		return res, nil
+	}
+
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	t.mtx.Lock()
+	t.digests[digestKey(req.URL, opt)] = challenge
+	t.mtx.Unlock()
+
+	req, err = newReq()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = t.apply(req, opt, transport); err != nil {
+		return nil, err
+	}
+
+	return do(req)
+}
+
+// HeaderValue returns the value of the Authorization header for
+// the given options. This is used for requests where the auth
+// can not be applied to an *http.Request, like gRPC calls.
+func (t *authenticator) HeaderValue(opt AuthOptions) (string, error) {
+	switch opt.AuthType() {
+	case AuthTypeOAuth2:
+		token, err := t.oauth2Token(opt, requester.TransportOptions{})
+		if err != nil {
+			return "", err
+		}
+		return token.headerValue(), nil
+	case AuthTypeApiKey, AuthTypeDigest, AuthTypeSigV4, AuthTypeHmac:
+		return "", fmt.Errorf("auth type %s is not supported for this request", opt.Type)
+	default:
+		return opt.HeaderValue(), nil
+	}
+}
+
+// apply sets the authentication specified in
+// opt to the given request.
+func (t *authenticator) apply(req *http.Request, opt AuthOptions, transport requester.TransportOptions) error {
+	switch opt.AuthType() {
+
+	case AuthTypeOAuth2:
+		token, err := t.oauth2Token(opt, transport)
+		if err != nil {
+			return errs.WithPrefix("failed acquiring oauth2 token:", err)
+		}
+		req.Header.Set("Authorization", token.headerValue())
+
+	case AuthTypeApiKey:
+		switch opt.In {
+		case "header":
+			name := opt.Name
+			if name == "" {
+				name = "X-Api-Key"
+			}
+			req.Header.Set(name, opt.Token)
+		case "query":
+			name := opt.Name
+			if name == "" {
+				name = "api_key"
+			}
+			query := req.URL.Query()
+			query.Set(name, opt.Token)
+			req.URL.RawQuery = query.Encode()
+		default:
+			return ErrInvalidApiKeyIn
+		}
+
+	case AuthTypeDigest:
+		t.mtx.Lock()
+		defer t.mtx.Unlock()
+		challenge, ok := t.digests[digestKey(req.URL, opt)]
+		if !ok {
+			// The challenge is obtained from the
+			// response to the first request.
+			return nil
+		}
+		v, err := challenge.authorize(req.Method, req.URL.RequestURI(), opt.UserName, opt.Password)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", v)
+
+	case AuthTypeSigV4:
+		if err := signSigV4(req, opt, time.Now()); err != nil {
+			return errs.WithPrefix("failed signing request:", err)
+		}
+
+	case AuthTypeHmac:
+		if err := signHmac(req, opt, time.Now()); err != nil {
+			return errs.WithPrefix("failed signing request:", err)
+		}
+
+	default:
+		req.Header.Set("Authorization", opt.HeaderValue())
+	}
+
+	return nil
+}
+
+type oauth2Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+
+	expiresAt time.Time
+}
+
+func (t *oauth2Token) expired() bool {
+	return !t.expiresAt.IsZero() && time.Now().After(t.expiresAt)
+}
+
+func (t *oauth2Token) headerValue() string {
+	typ := t.TokenType
+	if typ == "" || strings.EqualFold(typ, "bearer") {
+		typ = "Bearer"
+	}
+	return fmt.Sprintf("%s %s", typ, t.AccessToken)
+}
+
+// oauth2Token returns a cached token for the client specified in
+// opt. If no token has been cached or if the cached token has
+// expired, a new token is requested from the token URL.
+//
+// Only token requests for the same client are serialized, so
+// that a slow token endpoint does not block other requests.
+func (t *authenticator) oauth2Token(opt AuthOptions, transport requester.TransportOptions) (*oauth2Token, error) {
+	key := strings.Join([]string{opt.TokenURL, opt.Grant, opt.ClientID, opt.UserName, opt.Scope}, "|")
+
+	lock := t.tokenLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	t.mtx.Lock()
+	token, ok := t.tokens[key]
+	t.mtx.Unlock()
+
+	if ok && !token.expired() {
+		return token, nil
+	}
+
+	var err error
+	if ok && token.RefreshToken != "" {
+		token, err = t.requestToken(opt, transport, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {token.RefreshToken},
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("Refreshing oauth2 token failed; requesting new token")
+		}
+	}
+
+	if token == nil || err != nil {
+		token, err = t.requestToken(opt, transport, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t.mtx.Lock()
+	t.tokens[key] = token
+	t.mtx.Unlock()
+
+	return token, nil
+}
+
+// tokenLock returns the lock guarding the
+// token requests of the given client key.
+func (t *authenticator) tokenLock(key string) *sync.Mutex {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	lock, ok := t.tokenLocks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		t.tokenLocks[key] = lock
+	}
+	return lock
+}
+
+// requestToken requests a new token from the token URL specified
+// in opt using the given transport options. If params is nil, the
+// parameters are built from the grant type specified in opt.
+//
+// No cookies are sent with or stored from the token request.
+func (t *authenticator) requestToken(
+	opt AuthOptions,
+	transport requester.TransportOptions,
+	params url.Values,
+) (*oauth2Token, error) {
+	if opt.TokenURL == "" {
+		return nil, ErrNoTokenURL
+	}
+
+	if params == nil {
+		switch opt.Grant {
+		case GrantClientCredentials:
+			params = url.Values{"grant_type": {GrantClientCredentials}}
+		case GrantPassword:
+			params = url.Values{
+				"grant_type": {GrantPassword},
+				"username":   {opt.UserName},
+				"password":   {opt.Password},
+			}
+		default:
+			return nil, errs.WithSuffix(ErrInvalidGrant, fmt.Sprintf("(%s)", opt.Grant))
+		}
+	}
+
+	if opt.Scope != "" {
+		params.Set("scope", opt.Scope)
+	}
+
+	if opt.ClientSecret == "" && opt.ClientID != "" {
+		params.Set("client_id", opt.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(t.ctx, http.MethodPost, opt.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if opt.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(opt.ClientID), url.QueryEscape(opt.ClientSecret))
+	}
+
+	res, err := t.req.Do(req, requester.Options{
+		FollowRedirects:  true,
+		TransportOptions: transport,
+	})
+	if err != nil {
+		return nil, errs.WithPrefix("token request failed:", err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errs.WithPrefix("failed reading token response:", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("token request failed with status %s: %s", res.Status, string(data))
+	}
+
+	var token oauth2Token
+	err = json.Unmarshal(data, &token)
+	if err != nil {
+		return nil, errs.WithPrefix("failed decoding token response:", err)
+	}
+
+	if token.AccessToken == "" {
+		return nil, ErrNoAccessToken
+	}
+
+	if token.ExpiresIn > 0 {
+		token.expiresAt = time.Now().
+			Add(time.Duration(token.ExpiresIn) * time.Second).
+			Add(-tokenExpiryLeeway)
+	}
+
+	log.Debug().Fields(
+		"tokenurl", opt.TokenURL,
+		"grant", params.Get("grant_type"),
+		"expiresIn", token.ExpiresIn,
+	).Msg("Acquired oauth2 token")
+
+	return &token, nil
+}
+
+type digestChallenge struct {
+	Realm     string
+	Nonce     string
+	Opaque    string
+	Algorithm string
+	Qop       string
+
+	nc int
+}
+
+func digestKey(u *url.URL, opt AuthOptions) string {
+	return u.Host + "|" + opt.UserName
+}
+
+// parseDigestChallenge parses the first digest challenge
+// found in the given WWW-Authenticate header values.
+func parseDigestChallenge(values []string) (*digestChallenge, error) {
+	for _, v := range values {
+		scheme, params, ok := strings.Cut(strings.TrimSpace(v), " ")
+		if !ok || !strings.EqualFold(scheme, "digest") {
+			continue
+		}
+
+		var c digestChallenge
+		for key, val := range parseAuthParams(params) {
+			switch strings.ToLower(key) {
+			case "realm":
+				c.Realm = val
+			case "nonce":
+				c.Nonce = val
+			case "opaque":
+				c.Opaque = val
+			case "algorithm":
+				c.Algorithm = val
+			case "qop":
+				for _, q := range strings.Split(val, ",") {
+					if strings.TrimSpace(q) == "auth" {
+						c.Qop = "auth"
+					}
+				}
+				if c.Qop == "" {
+					return nil, errs.WithSuffix(ErrUnsupportedDigest, fmt.Sprintf("(qop=%s)", val))
+				}
+			}
+		}
+
+		return &c, nil
+	}
+
+	return nil, ErrUnsupportedDigest
+}
+
+// parseAuthParams parses a comma separated list of
+// key=value pairs where values might be quoted.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,")
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		key = strings.TrimSpace(key)
+
+		var val string
+		if strings.HasPrefix(rest, `"`) {
+			var sb strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				sb.WriteByte(rest[i])
+			}
+			val = sb.String()
+			s = rest[min(i+1, len(rest)):]
+		} else {
+			val, s, _ = strings.Cut(rest, ",")
+			val = strings.TrimSpace(val)
+		}
+
+		params[key] = val
+	}
+
+	return params
+}
+
+// authorize returns the value of the Authorization header answering
+// the challenge for the given request method and URI as specified
+// in RFC 7616.
+func (t *digestChallenge) authorize(method, uri, username, password string) (string, error) {
+	var newHash func() hash.Hash
+	algorithm := strings.ToUpper(t.Algorithm)
+	switch strings.TrimSuffix(algorithm, "-SESS") {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", errs.WithSuffix(ErrUnsupportedDigest, fmt.Sprintf("(algorithm=%s)", t.Algorithm))
+	}
+
+	h := func(s string) string {
+		hsh := newHash()
+		io.WriteString(hsh, s)
+		return hex.EncodeToString(hsh.Sum(nil))
+	}
+
+	var cnonceBuf [16]byte
+	if _, err := rand.Read(cnonceBuf[:]); err != nil {
+		return "", err
+	}
+	cnonce := hex.EncodeToString(cnonceBuf[:])
+
+	t.nc++
+	nc := fmt.Sprintf("%08x", t.nc)
+
+	ha1 := h(fmt.Sprintf("%s:%s:%s", username, t.Realm, password))
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = h(fmt.Sprintf("%s:%s:%s", ha1, t.Nonce, cnonce))
+	}
+	ha2 := h(fmt.Sprintf("%s:%s", method, uri))
+
+	var response string
+	if t.Qop != "" {
+		response = h(fmt.Sprintf("%s:%s:%s:%s:%s:%s", ha1, t.Nonce, nc, cnonce, t.Qop, ha2))
+	} else {
+		response = h(fmt.Sprintf("%s:%s:%s", ha1, t.Nonce, ha2))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		username, t.Realm, t.Nonce, uri, response)
+	if t.Algorithm != "" {
+		fmt.Fprintf(&sb, `, algorithm=%s`, t.Algorithm)
+	}
+	if t.Qop != "" {
+		fmt.Fprintf(&sb, `, qop=%s, nc=%s, cnonce="%s"`, t.Qop, nc, cnonce)
+	}
+	if t.Opaque != "" {
+		fmt.Fprintf(&sb, `, opaque="%s"`, t.Opaque)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,313 @@
+package executor
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestAuthenticator_OAuth2(t *testing.T) {
+	var tokenRequests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Nil(t, r.ParseForm())
+		grant := r.PostForm.Get("grant_type")
+		tokenRequests = append(tokenRequests, grant)
+
+		switch grant {
+		case GrantClientCredentials:
+			id, secret, _ := r.BasicAuth()
+			if id != "client" || secret != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case GrantPassword:
+			if r.PostForm.Get("username") != "foo" || r.PostForm.Get("password") != "bar" ||
+				r.PostForm.Get("client_id") != "client" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "refresh" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "token-endpoint"})
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600,"refresh_token":"refresh"}`,
+			len(tokenRequests))
+	}))
+	defer srv.Close()
+
+	t.Run("client-credentials", func(t *testing.T) {
+		tokenRequests = nil
+		auth := newAuthenticator(context.Background(), requester.NewHttpWithCookies(func(*http.Client) {}))
+		opt, _ := AuthOptionsFromMap(map[string]any{
+			"type":         "oauth2",
+			"tokenurl":     srv.URL,
+			"clientid":     "client",
+			"clientsecret": "secret",
+		})
+
+		v, err := auth.HeaderValue(opt)
+		require.Nil(t, err)
+		assert.Equal(t, "Bearer token-1", v)
+
+		// Token is cached.
+		v, err = auth.HeaderValue(opt)
+		require.Nil(t, err)
+		assert.Equal(t, "Bearer token-1", v)
+		assert.Equal(t, []string{GrantClientCredentials}, tokenRequests)
+
+		// Expired tokens are refreshed.
+		for _, token := range auth.tokens {
+			token.expiresAt = time.Now().Add(-time.Second)
+		}
+		v, err = auth.HeaderValue(opt)
+		require.Nil(t, err)
+		assert.Equal(t, "Bearer token-2", v)
+		assert.Equal(t, []string{GrantClientCredentials, "refresh_token"}, tokenRequests)
+	})
+
+	t.Run("password", func(t *testing.T) {
+		tokenRequests = nil
+		auth := newAuthenticator(context.Background(), requester.NewHttpWithCookies(func(*http.Client) {}))
+		opt, _ := AuthOptionsFromMap(map[string]any{
+			"type":     "oauth2",
+			"grant":    "password",
+			"tokenurl": srv.URL,
+			"clientid": "client",
+			"username": "foo",
+			"password": "bar",
+		})
+
+		v, err := auth.HeaderValue(opt)
+		require.Nil(t, err)
+		assert.Equal(t, "Bearer token-1", v)
+	})
+
+	t.Run("invalid-credentials", func(t *testing.T) {
+		auth := newAuthenticator(context.Background(), requester.NewHttpWithCookies(func(*http.Client) {}))
+		opt, _ := AuthOptionsFromMap(map[string]any{
+			"type":         "oauth2",
+			"tokenurl":     srv.URL,
+			"clientid":     "client",
+			"clientsecret": "wrong",
+		})
+
+		_, err := auth.HeaderValue(opt)
+		assert.Error(t, err)
+	})
+
+	t.Run("no-cookies", func(t *testing.T) {
+		req := requester.NewHttpWithCookies(func(*http.Client) {})
+		auth := newAuthenticator(context.Background(), req)
+		opt, _ := AuthOptionsFromMap(map[string]any{
+			"type":         "oauth2",
+			"tokenurl":     srv.URL,
+			"clientid":     "client",
+			"clientsecret": "secret",
+		})
+
+		_, err := auth.HeaderValue(opt)
+		require.Nil(t, err)
+
+		u, _ := url.Parse(srv.URL)
+		assert.Empty(t, req.CookieJar("default").Cookies(u))
+	})
+
+	t.Run("transport-options", func(t *testing.T) {
+		tokenRequests = nil
+		auth := newAuthenticator(context.Background(), requester.NewHttpWithCookies(func(*http.Client) {}))
+		u, _ := url.Parse(srv.URL)
+		opt, _ := AuthOptionsFromMap(map[string]any{
+			"type":         "oauth2",
+			"tokenurl":     "http://token.test:" + u.Port(),
+			"clientid":     "client",
+			"clientsecret": "secret",
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		err := auth.apply(req, opt, requester.TransportOptions{
+			Resolve: []string{"token.test:" + u.Port() + ":127.0.0.1"},
+		})
+		require.Nil(t, err)
+		assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+	})
+
+	t.Run("canceled-context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		auth := newAuthenticator(ctx, requester.NewHttpWithCookies(func(*http.Client) {}))
+		opt, _ := AuthOptionsFromMap(map[string]any{
+			"type":         "oauth2",
+			"tokenurl":     srv.URL,
+			"clientid":     "client",
+			"clientsecret": "secret",
+		})
+
+		// Token requests are performed in teardown steps
+		// as well, which are not affected by cancellation.
+		_, err := auth.HeaderValue(opt)
+		assert.Nil(t, err)
+	})
+
+	t.Run("concurrent-clients", func(t *testing.T) {
+		release := make(chan struct{})
+		slowSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"slow"}`))
+		}))
+		defer slowSrv.Close()
+		defer close(release)
+
+		auth := newAuthenticator(context.Background(), requester.NewHttpWithCookies(func(*http.Client) {}))
+		slowOpt, _ := AuthOptionsFromMap(map[string]any{
+			"type":         "oauth2",
+			"tokenurl":     slowSrv.URL,
+			"clientid":     "client",
+			"clientsecret": "secret",
+		})
+		opt, _ := AuthOptionsFromMap(map[string]any{
+			"type":         "oauth2",
+			"tokenurl":     srv.URL,
+			"clientid":     "client",
+			"clientsecret": "secret",
+		})
+
+		go auth.HeaderValue(slowOpt)
+		time.Sleep(50 * time.Millisecond)
+
+		done := make(chan error)
+		go func() {
+			_, err := auth.HeaderValue(opt)
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			assert.Nil(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("token request blocked by token request of another client")
+		}
+	})
+
+	t.Run("no-tokenurl", func(t *testing.T) {
+		auth := newAuthenticator(context.Background(), requester.NewHttpWithCookies(func(*http.Client) {}))
+		opt, _ := AuthOptionsFromMap(map[string]any{"type": "oauth2"})
+
+		_, err := auth.HeaderValue(opt)
+		assert.ErrorIs(t, err, ErrNoTokenURL)
+	})
+}
+
+func TestAuthenticator_Digest(t *testing.T) {
+	const (
+		realm = "test"
+		nonce = "abc123"
+	)
+
+	h := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Digest realm="%s", nonce="%s", qop="auth,auth-int", opaque="xyz"`, realm, nonce))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		params := parseAuthParams(header[len("Digest "):])
+		ha1 := h(fmt.Sprintf("foo:%s:bar", realm))
+		ha2 := h(fmt.Sprintf("%s:%s", r.Method, params["uri"]))
+		expected := h(fmt.Sprintf("%s:%s:%s:%s:%s:%s",
+			ha1, nonce, params["nc"], params["cnonce"], params["qop"], ha2))
+
+		if params["response"] != expected || params["opaque"] != "xyz" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		io.WriteString(w, params["nc"])
+	}))
+	defer srv.Close()
+
+	req := requester.NewHttpWithCookies(func(*http.Client) {})
+	auth := newAuthenticator(context.Background(), req)
+	opt, _ := AuthOptionsFromMap(map[string]any{
+		"type":     "digest",
+		"username": "foo",
+		"password": "bar",
+	})
+
+	newReq := func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, srv.URL+"/foo?bar=baz", nil)
+	}
+	do := func(r *http.Request) (*http.Response, error) {
+		return req.Do(r, requester.Options{})
+	}
+
+	res, err := auth.Do(opt, requester.TransportOptions{}, newReq, do)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "00000001", string(body))
+
+	// The challenge is reused for subsequent requests.
+	res, err = auth.Do(opt, requester.TransportOptions{}, newReq, do)
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	body, _ = io.ReadAll(res.Body)
+	assert.Equal(t, "00000002", string(body))
+}
+
+func TestAuthenticator_ApiKey(t *testing.T) {
+	auth := newAuthenticator(context.Background(), nil)
+
+	t.Run("header", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		opt, _ := AuthOptionsFromMap(map[string]any{"type": "apikey", "token": "key"})
+
+		require.Nil(t, auth.apply(req, opt, requester.TransportOptions{}))
+		assert.Equal(t, "key", req.Header.Get("X-Api-Key"))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com?foo=bar", nil)
+		opt, _ := AuthOptionsFromMap(map[string]any{
+			"type":  "apikey",
+			"token": "key",
+			"in":    "query",
+			"name":  "key",
+		})
+
+		require.Nil(t, auth.apply(req, opt, requester.TransportOptions{}))
+		assert.Equal(t, "foo=bar&key=key", req.URL.RawQuery)
+		assert.Empty(t, req.Header.Get("Authorization"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		opt, _ := AuthOptionsFromMap(map[string]any{"type": "apikey", "token": "key", "in": "body"})
+
+		assert.ErrorIs(t, auth.apply(req, opt, requester.TransportOptions{}), ErrInvalidApiKeyIn)
+	})
+}
@@ -0,0 +1,323 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/studio-b12/goat/pkg/requester"
+	"github.com/studio-b12/goat/pkg/util"
+)
+
+var (
+	ErrInvalidCookie = errors.New("cookie must be a Set-Cookie string or an object with at least Name set")
+	ErrUnknownEngine = errors.New("unknown script engine")
+	ErrNoRequestURL  = errors.New("no url has been specified")
+
+	ErrFunctionsNotSupported = errors.New("the script engine does not support template functions")
+	ErrTranspileNotSupported = errors.New("the script engine does not support transpiling scripts")
+)
+
+// engineOptionName is the name of the option in the
+// Defaults section of a Goatfile which selects the
+// engine used to execute the Goatfile.
+const engineOptionName = "engine"
+
+// transpileOptionName is the name of the option in the
+// Defaults section of a Goatfile which enables or disables
+// transpiling the scripts of the Goatfile.
+const transpileOptionName = "transpile"
+
+// newEngine creates a new instance of the default engine
+// and registers the builtins provided by the executor.
+func (t *Executor) newEngine() engine.Engine {
+	return t.registerBuiltins(t.engineMaker())
+}
+
+// newEngineFor creates a new instance of the engine selected
+// by the engine option in the Defaults section of the given
+// Goatfile. If no engine is specified, the default engine
+// is used.
+//
+// If the transpile option is specified in the Defaults section,
+// transpiling is enabled or disabled for the created engine.
+func (t *Executor) newEngineFor(gf goatfile.Goatfile) (engine.Engine, error) {
+	if gf.Defaults == nil {
+		return t.newEngine(), nil
+	}
+
+	eng := t.engineMaker
+	if name, ok := gf.Defaults.Options[engineOptionName]; ok {
+		eng, ok = t.Engines[fmt.Sprint(name)]
+		if !ok {
+			return nil, errs.WithSuffix(ErrUnknownEngine, fmt.Sprintf("(%v)", name))
+		}
+	}
+
+	instance := eng()
+
+	if enabled, ok := gf.Defaults.Options[transpileOptionName].(bool); ok {
+		transpiler, ok := instance.(engine.Transpiler)
+		if !ok {
+			if enabled {
+				return nil, ErrTranspileNotSupported
+			}
+		} else {
+			transpiler.SetTranspile(enabled)
+		}
+	}
+
+	return t.registerBuiltins(instance), nil
+}
+
+// registerBuiltins registers the builtins provided by
+// the executor to the given engine.
+func (t *Executor) registerBuiltins(eng engine.Engine) engine.Engine {
+	if jars, ok := t.req.(requester.CookieJars); ok {
+		registerCookieBuiltins(eng, jars)
+	}
+	registerHttpBuiltins(eng, t.ctx, t.req)
+
+	return eng
+}
+
+// loadTemplateFuncs executes the Functions block of the given
+// request in the given engine and sets the exported functions
+// as template functions of the request.
+func loadTemplateFuncs(eng engine.Engine, req *goatfile.Request) error {
+	if goatfile.IsNoContent(req.Functions) {
+		return nil
+	}
+
+	loader, ok := eng.(engine.FunctionLoader)
+	if !ok {
+		return ErrFunctionsNotSupported
+	}
+
+	functions, err := util.ReadReaderToString(req.Functions.Reader())
+	if err != nil {
+		return errs.WithPrefix("reading functions failed:", err)
+	}
+
+	funcs, err := loader.LoadFunctions(functions)
+	if err != nil {
+		return errs.WithPrefix("loading functions failed:",
+			newScriptError(err, "Functions", functions, req.FunctionsPos))
+	}
+
+	req.TemplateFuncs = funcs
+	return nil
+}
+
+// registerCookieBuiltins registers builtins to inspect and
+// manipulate the cookie jars of the given CookieJars.
+func registerCookieBuiltins(eng engine.Engine, jars requester.CookieJars) {
+	eng.SetBuiltin("getCookies", func(jar any) []Cookie {
+		httpCookies := jars.CookieJar(jar).All()
+		cookies := make([]Cookie, 0, len(httpCookies))
+		for _, c := range httpCookies {
+			cookies = append(cookies, cookieFromHttp(c))
+		}
+		return cookies
+	})
+
+	eng.SetBuiltin("getCookie", func(jar any, name string) *Cookie {
+		for _, c := range jars.CookieJar(jar).All() {
+			if c.Name == name {
+				cookie := cookieFromHttp(c)
+				return &cookie
+			}
+		}
+		return nil
+	})
+
+	eng.SetBuiltin("setCookie", func(jar any, rawURL string, cookie any) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return err
+		}
+		c, err := toHttpCookie(cookie)
+		if err != nil {
+			return err
+		}
+		jars.CookieJar(jar).SetCookies(u, []*http.Cookie{c})
+		return nil
+	})
+
+	eng.SetBuiltin("clearCookies", func(jar any) {
+		jars.CookieJar(jar).Clear()
+	})
+}
+
+// registerHttpBuiltins registers the http object providing
+// functions to perform HTTP requests with the given Requester
+// from within scripts.
+//
+// Requests are performed with the values of ctx but are not
+// affected by its cancellation, so that they can be used in
+// teardown steps as well.
+func registerHttpBuiltins(eng engine.Engine, ctx context.Context, req requester.Requester) {
+	ctx = context.WithoutCancel(ctx)
+	eng.SetBuiltin("http", map[string]any{
+		"request": func(params map[string]any) (Response, error) {
+			return doScriptRequest(ctx, req, params)
+		},
+	})
+}
+
+// doScriptRequest performs the HTTP request described by the
+// given params with the given Requester and returns the response.
+//
+// Besides method, url, headers and body, all request options
+// like cookiejar or responsetype are taken from params.
+func doScriptRequest(ctx context.Context, req requester.Requester, params map[string]any) (Response, error) {
+	method := "GET"
+	if v, ok := params["method"]; ok {
+		method = strings.ToUpper(fmt.Sprint(v))
+	}
+
+	rawURL, _ := params["url"].(string)
+	if rawURL == "" {
+		return Response{}, ErrNoRequestURL
+	}
+
+	header := http.Header{}
+	if headers, ok := params["headers"].(map[string]any); ok {
+		for key, val := range headers {
+			switch vt := val.(type) {
+			case []any:
+				for _, v := range vt {
+					header.Add(key, fmt.Sprint(v))
+				}
+			default:
+				header.Set(key, fmt.Sprint(vt))
+			}
+		}
+	}
+
+	body, err := scriptRequestBody(params["body"], header)
+	if err != nil {
+		return Response{}, errs.WithPrefix("failed encoding body:", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return Response{}, errs.WithPrefix("failed creating http request:", err)
+	}
+	httpReq.Header = header
+
+	trace := newTracer()
+	httpReq = httpReq.WithContext(httptrace.WithClientTrace(httpReq.Context(), trace.clientTrace()))
+
+	httpResp, err := req.Do(httpReq, requester.OptionsFromMap(params))
+	if err != nil {
+		return Response{}, errs.WithPrefix("http request failed:", err)
+	}
+
+	resp, err := FromHttpResponse(httpResp, params)
+	if err != nil {
+		return Response{}, errs.WithPrefix("response interpretation failed:", err)
+	}
+
+	resp.Timings = trace.Timings(time.Now())
+
+	return resp, nil
+}
+
+// scriptRequestBody returns a reader of the given body passed
+// to http.request. Strings and raw data are sent as they are.
+// Other values are encoded as JSON and the Content-Type header
+// is set accordingly, if not specified.
+func scriptRequestBody(body any, header http.Header) (io.Reader, error) {
+	switch vt := body.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.NewReader(vt), nil
+	case []byte:
+		return bytes.NewReader(vt), nil
+	case RawData:
+		return bytes.NewReader(vt), nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json")
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// toHttpCookie converts the given value, which is either
+// a Set-Cookie string or a map of cookie attributes, to
+// an http.Cookie.
+func toHttpCookie(v any) (*http.Cookie, error) {
+	switch vt := v.(type) {
+	case string:
+		cookies := cookiesFromHeader(http.Header{"Set-Cookie": {vt}})
+		if len(cookies) == 0 {
+			return nil, ErrInvalidCookie
+		}
+		return cookies[0].toHttp(), nil
+
+	case map[string]any:
+		var c Cookie
+		for key, val := range vt {
+			switch strings.ToLower(key) {
+			case "name":
+				c.Name = fmt.Sprint(val)
+			case "value":
+				c.Value = fmt.Sprint(val)
+			case "path":
+				c.Path = fmt.Sprint(val)
+			case "domain":
+				c.Domain = fmt.Sprint(val)
+			case "expires":
+				switch exp := val.(type) {
+				case time.Time:
+					c.Expires = exp
+				case string:
+					t, err := time.Parse(time.RFC3339, exp)
+					if err != nil {
+						return nil, err
+					}
+					c.Expires = t
+				}
+			case "maxage":
+				switch age := val.(type) {
+				case int64:
+					c.MaxAge = int(age)
+				case float64:
+					c.MaxAge = int(age)
+				}
+			case "secure":
+				c.Secure, _ = val.(bool)
+			case "httponly":
+				c.HttpOnly, _ = val.(bool)
+			case "samesite":
+				c.SameSite = fmt.Sprint(val)
+			}
+		}
+		if c.Name == "" {
+			return nil, ErrInvalidCookie
+		}
+		return c.toHttp(), nil
+
+	default:
+		return nil, ErrInvalidCookie
+	}
+}
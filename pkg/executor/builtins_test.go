@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestCookieBuiltins(t *testing.T) {
+	req := requester.NewHttpWithCookies(func(*http.Client) {})
+	eng := engine.NewGoja()
+	registerCookieBuiltins(eng, req)
+
+	u, _ := url.Parse("https://example.com/")
+	req.CookieJar("admin").SetCookies(u, []*http.Cookie{{Name: "session", Value: "abc", Path: "/"}})
+
+	err := eng.Run(`
+		const cookies = getCookies("admin");
+		assert(cookies.length === 1);
+		assert(cookies[0].Name === "session");
+		assert(cookies[0].Domain === "example.com");
+		assert(getCookie("admin", "session").Value === "abc");
+		assert(getCookie("admin", "nope") === null);
+		assert(getCookies("default").length === 0);
+
+		setCookie("default", "https://example.com/api", {name: "token", value: "foo", path: "/api", httpOnly: true});
+		setCookie("default", "https://example.com/", "theme=dark; Path=/; SameSite=Lax");
+		clearCookies("admin");
+	`)
+	require.Nil(t, err)
+
+	assert.Empty(t, req.CookieJar("admin").All())
+
+	apiURL, _ := url.Parse("https://example.com/api/users")
+	cookies := req.CookieJar("default").Cookies(apiURL)
+	assert.Len(t, cookies, 2)
+
+	all := req.CookieJar("default").All()
+	require.Len(t, all, 2)
+	assert.Equal(t, "theme", all[0].Name)
+	assert.Equal(t, http.SameSiteLaxMode, all[0].SameSite)
+	assert.Equal(t, "token", all[1].Name)
+	assert.True(t, all[1].HttpOnly)
+
+	err = eng.Run(`setCookie("default", "https://example.com/", {value: "foo"})`)
+	assert.Error(t, err)
+}
+
+func TestResponseCookies(t *testing.T) {
+	resp, err := FromHttpResponse(&http.Response{
+		Header: http.Header{"Set-Cookie": {
+			"session=abc; Path=/; HttpOnly; Secure; SameSite=Strict",
+			"pref=dark; Max-Age=3600",
+		}},
+		Body: http.NoBody,
+	}, nil)
+	require.Nil(t, err)
+
+	assert.Equal(t, []Cookie{
+		{Name: "session", Value: "abc", Path: "/", HttpOnly: true, Secure: true, SameSite: "Strict"},
+		{Name: "pref", Value: "dark", MaxAge: 3600},
+	}, resp.Cookies)
+}
+
+func TestHttpBuiltins(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		}
+		session := ""
+		if c, err := r.Cookie("session"); err == nil {
+			session = c.Value
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"contentType": r.Header.Get("Content-Type"),
+			"token":       r.Header.Get("X-Token"),
+			"body":        string(body),
+			"session":     session,
+		})
+	}))
+	defer srv.Close()
+
+	req := requester.NewHttpWithCookies(func(*http.Client) {})
+	eng := engine.NewGoja()
+	registerHttpBuiltins(eng, context.Background(), req)
+	eng.Set("instance", srv.URL)
+
+	err := eng.Run(`
+		var login = http.request({method: "post", url: instance + "/login", body: {user: "goat"}, cookiejar: "admin"});
+		var me = http.request({url: instance + "/me", headers: {"X-Token": "foo"}, cookiejar: "admin"});
+		var anon = http.request({url: instance + "/me", body: "raw"});
+	`)
+	require.Nil(t, err)
+
+	state := eng.State()
+	assert.NotContains(t, state, "http")
+
+	login := state["login"].(Response)
+	assert.Equal(t, 200, login.StatusCode)
+	assert.Equal(t, map[string]any{
+		"method":      "POST",
+		"path":        "/login",
+		"contentType": "application/json",
+		"token":       "",
+		"body":        `{"user":"goat"}`,
+		"session":     "",
+	}, login.Body)
+	require.Len(t, login.Cookies, 1)
+	assert.Equal(t, "session", login.Cookies[0].Name)
+
+	me := state["me"].(Response).Body.(map[string]any)
+	assert.Equal(t, "GET", me["method"])
+	assert.Equal(t, "foo", me["token"])
+	assert.Equal(t, "abc", me["session"])
+
+	anon := state["anon"].(Response).Body.(map[string]any)
+	assert.Equal(t, "raw", anon["body"])
+	assert.Equal(t, "", anon["session"])
+
+	err = eng.Run(`http.request({method: "GET"})`)
+	assert.ErrorIs(t, err, ErrNoRequestURL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	canceledEng := engine.NewGoja()
+	registerHttpBuiltins(canceledEng, ctx, req)
+	canceledEng.Set("instance", srv.URL)
+	err = canceledEng.Run(`var teardown = http.request({url: instance + "/logout"});`)
+	require.Nil(t, err)
+	assert.Equal(t, 200, canceledEng.State()["teardown"].(Response).StatusCode)
+
+	exprEng := engine.NewExpr()
+	registerHttpBuiltins(exprEng, context.Background(), req)
+	assert.NotContains(t, exprEng.State(), "http")
+}
@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Cookie is the model of a cookie passed
+// into the engine state.
+type Cookie struct {
+	Name     string
+	Value    string
+	Path     string
+	Domain   string
+	Expires  time.Time
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	SameSite string
+}
+
+// cookiesFromHeader parses the Set-Cookie
+// entries of the given response header.
+func cookiesFromHeader(header http.Header) []Cookie {
+	res := http.Response{Header: header}
+	httpCookies := res.Cookies()
+
+	cookies := make([]Cookie, 0, len(httpCookies))
+	for _, c := range httpCookies {
+		cookies = append(cookies, cookieFromHttp(c))
+	}
+
+	return cookies
+}
+
+func cookieFromHttp(c *http.Cookie) Cookie {
+	return Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		Expires:  c.Expires,
+		MaxAge:   c.MaxAge,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+		SameSite: sameSiteName(c.SameSite),
+	}
+}
+
+func (t Cookie) toHttp() *http.Cookie {
+	return &http.Cookie{
+		Name:     t.Name,
+		Value:    t.Value,
+		Path:     t.Path,
+		Domain:   t.Domain,
+		Expires:  t.Expires,
+		MaxAge:   t.MaxAge,
+		Secure:   t.Secure,
+		HttpOnly: t.HttpOnly,
+		SameSite: sameSiteMode(t.SameSite),
+	}
+}
+
+func sameSiteMode(v string) http.SameSite {
+	switch strings.ToLower(v) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteDefaultMode
+	}
+}
+
+func sameSiteName(v http.SameSite) string {
+	switch v {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+// DebugAction defines how the Executor proceeds
+// after a Debugger has been called.
+type DebugAction int
+
+const (
+	// DebugContinue proceeds with the execution
+	// of the request.
+	DebugContinue DebugAction = iota
+	// DebugSkip skips the request. It has no effect
+	// when returned after the response has been received.
+	DebugSkip
+	// DebugResend sends the request again. It has no
+	// effect when returned before the request has been sent.
+	DebugResend
+)
+
+// Debugger can be set to the Executor to inspect and
+// manipulate the execution of requests.
+//
+// The passed engine is the engine of the current batch,
+// which can be used to read and modify the state or to
+// evaluate expressions. Modifications to the passed
+// request are applied to the request which is sent.
+//
+// To abort the execution, cancel the context passed
+// to the Executor.
+type Debugger interface {
+	// BeforeRequest is called with the fully substituted
+	// request right before it is sent.
+	BeforeRequest(req *goatfile.Request, eng engine.Engine) DebugAction
+
+	// AfterResponse is called after the request has been
+	// sent with either the received response or the error
+	// which occurred. The response is already available
+	// in the state of the engine. It is called before the
+	// Script section of the request is executed.
+	AfterResponse(req *goatfile.Request, resp Response, err error, eng engine.Engine) DebugAction
+}
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+type debuggerMock struct {
+	before func(req *goatfile.Request, eng engine.Engine) DebugAction
+	after  func(req *goatfile.Request, resp Response, err error, eng engine.Engine) DebugAction
+}
+
+func (t debuggerMock) BeforeRequest(req *goatfile.Request, eng engine.Engine) DebugAction {
+	return t.before(req, eng)
+}
+
+func (t debuggerMock) AfterResponse(req *goatfile.Request, resp Response, err error, eng engine.Engine) DebugAction {
+	return t.after(req, resp, err, eng)
+}
+
+func TestExecute_Debugger(t *testing.T) {
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.goat": `
+GET {{.instance}}/a
+
+---
+
+GET {{.instance}}/b
+
+---
+
+GET {{.instance}}/c
+
+[Script]
+assert(resent === true);
+assert(response.BodyRaw.toString() === "/c");
+`,
+	})
+
+	var resent bool
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+	exec.Debugger = debuggerMock{
+		before: func(req *goatfile.Request, eng engine.Engine) DebugAction {
+			switch req.PosLine {
+			case 2:
+				assert.Equal(t, srv.URL+"/a", req.URI)
+				req.URI = srv.URL + "/edited"
+			case 6:
+				return DebugSkip
+			}
+			return DebugContinue
+		},
+		after: func(req *goatfile.Request, resp Response, err error, eng engine.Engine) DebugAction {
+			require.Nil(t, err)
+			assert.Equal(t, resp, eng.State()["response"])
+			if req.PosLine == 10 && !resent {
+				resent = true
+				return DebugResend
+			}
+			eng.Set("resent", resent)
+			return DebugContinue
+		},
+	}
+
+	_, err := exec.Execute([]string{filepath.Join(dir, "test.goat")}, engine.State{"instance": srv.URL}, false)
+	require.Nil(t, err)
+
+	assert.Equal(t, map[string]int{"/edited": 1, "/c": 2}, calls)
+}
@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+)
+
+func TestExecute_SelectEngine(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 5, "name": "goat"}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"expr.goat": `
+### Defaults
+
+[Options]
+engine = "expr"
+
+### Tests
+
+GET {{.instance}}
+
+[Script]
+assert(response.StatusCode == 200, "invalid status")
+userId = response.Body.id
+greeting = "hello " + response.Body.name
+`,
+		"failing.goat": `
+### Defaults
+
+[Options]
+engine = "expr"
+
+### Tests
+
+GET {{.instance}}
+
+[Script]
+assert(response.Body.id == 6, "invalid id")
+`,
+		"unknown.goat": `
+### Defaults
+
+[Options]
+engine = "lua"
+
+### Tests
+
+GET {{.instance}}
+`,
+	})
+
+	params := engine.State{"instance": srv.URL}
+
+	t.Run("expr", func(t *testing.T) {
+		eng, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "expr.goat"), params)
+		require.Nil(t, err)
+
+		require.IsType(t, &engine.Expr{}, eng)
+		state := eng.State()
+		assert.Equal(t, 5.0, state["userId"])
+		assert.Equal(t, "hello goat", state["greeting"])
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		_, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "failing.goat"), params)
+		require.NotNil(t, err)
+
+		pos, ok := errorSourcePos(err)
+		require.True(t, ok, err)
+		assert.Equal(t, 12, pos.Line)
+		assert.Equal(t, 1, pos.Column)
+		assert.Contains(t, err.Error(), "assertion failed: invalid id")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "unknown.goat"), params)
+		assert.ErrorIs(t, err, ErrUnknownEngine)
+	})
+}
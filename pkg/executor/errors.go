@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/studio-b12/goat/pkg/engine"
 	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/goatfile"
 	"github.com/zekrotja/rogu/log"
 )
 
 var (
-	ErrCanceled = errors.New("canceled")
+	ErrCanceled       = errors.New("canceled")
+	ErrInvalidGrpcURI = errors.New("grpc URI must be in the format 'host:port/package.Service/Method'")
 )
 
 type BatchExecutionError struct {
@@ -111,3 +114,110 @@ func NewNoAbortError(err error) error {
 		},
 	}
 }
+
+// RequestError wraps an error occurred during the
+// execution of a request alongside the request and
+// the received response, if available.
+type RequestError struct {
+	errs.InnerError
+
+	Request  goatfile.Request
+	Response *Response
+}
+
+func newRequestError(err error, req *goatfile.Request, resp *Response) error {
+	return &RequestError{
+		InnerError: errs.InnerError{
+			Inner: err,
+		},
+		Request:  *req,
+		Response: resp,
+	}
+}
+
+// ScriptError wraps an error occurred during the execution
+// of the PreScript or Script block of a request alongside
+// the executed source.
+type ScriptError struct {
+	errs.InnerError
+
+	Block  string
+	Source string
+
+	// Pos is the position of the start of Source
+	// in its source file, if known.
+	Pos goatfile.SourcePos
+
+	// Line and Column of the error in Source, starting
+	// at 1. Both are 0 if the position is unknown.
+	Line   int
+	Column int
+}
+
+func newScriptError(err error, block, source string, pos goatfile.SourcePos) error {
+	var t ScriptError
+	t.Inner = err
+	t.Block = block
+	t.Source = source
+	t.Pos = pos
+	t.Line, t.Column, _ = engine.ErrorPosition(err)
+
+	return &t
+}
+
+// SourcePos returns the position of the error in the
+// source file of the script. ok is false if either the
+// position of the script or of the error in the script
+// is unknown.
+func (t *ScriptError) SourcePos() (pos goatfile.SourcePos, ok bool) {
+	if t.Pos.IsZero() || t.Line == 0 {
+		return goatfile.SourcePos{}, false
+	}
+	return t.Pos.Offset(t.Line, t.Column), true
+}
+
+// withSourcePos prefixes err with the position in the
+// source file it occurred at. If this position is unknown,
+// the position of the request is appended instead.
+func withSourcePos(err error, req *goatfile.Request) error {
+	if pos, ok := errorSourcePos(err); ok {
+		return errs.WithPrefix(pos.String()+":", err)
+	}
+	return errs.WithSuffix(err, fmt.Sprintf("(%s:%d)", req.Path, req.PosLine))
+}
+
+// errorSourcePos returns the position in a source file
+// at which the given error occurred, if known.
+func errorSourcePos(err error) (goatfile.SourcePos, bool) {
+	if scriptErr, ok := errs.As[*ScriptError](err); ok {
+		return scriptErr.SourcePos()
+	}
+	if srcErr, ok := errs.As[*goatfile.SourceError](err); ok {
+		return srcErr.Pos, true
+	}
+	return goatfile.SourcePos{}, false
+}
+
+// RequestErrors returns all RequestErrors contained in
+// the given error and the errors wrapped by it in the
+// order of their occurrence.
+func RequestErrors(err error) (reqErrs []*RequestError) {
+	if err == nil {
+		return nil
+	}
+
+	if reqErr, ok := err.(*RequestError); ok {
+		return []*RequestError{reqErr}
+	}
+
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range wrapped.Unwrap() {
+			reqErrs = append(reqErrs, RequestErrors(inner)...)
+		}
+	case interface{ Unwrap() error }:
+		reqErrs = RequestErrors(wrapped.Unwrap())
+	}
+
+	return reqErrs
+}
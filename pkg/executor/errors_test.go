@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestExecute_AbortError(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.goat": `
+GET {{.instance}}/noabort
+
+[Script]
+assert(response.StatusCode === 200, "noabort failed");
+
+[Options]
+noabort = true
+
+---
+
+GET {{.instance}}/abort
+
+[Script]
+assert(response.StatusCode === 200, "abort failed");
+
+---
+
+GET {{.instance}}/skipped
+`,
+	})
+
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+	_, err := exec.Execute([]string{filepath.Join(dir, "test.goat")},
+		engine.State{"instance": srv.URL}, false)
+	require.NotNil(t, err)
+
+	// Only the error which aborted the execution is returned.
+	assert.Contains(t, err.Error(), "abort failed")
+	assert.NotContains(t, err.Error(), "noabort failed")
+	assert.Equal(t, []string{"/noabort", "/abort"}, calls)
+}
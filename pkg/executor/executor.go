@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/http"
+	"net/http/httptrace"
 	"os"
 	"path"
 	"path/filepath"
@@ -16,7 +18,9 @@ import (
 	"github.com/studio-b12/goat/pkg/engine"
 	"github.com/studio-b12/goat/pkg/errs"
 	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/studio-b12/goat/pkg/grpcrequester"
 	"github.com/studio-b12/goat/pkg/requester"
+	"github.com/studio-b12/goat/pkg/set"
 	"github.com/studio-b12/goat/pkg/util"
 	"github.com/zekrotja/rogu"
 	"github.com/zekrotja/rogu/log"
@@ -26,13 +30,36 @@ import (
 type Executor struct {
 	engineMaker func() engine.Engine
 	req         requester.Requester
-
-	ctx context.Context
-
-	Dry     bool
-	NoAbort bool
-	Skip    []string
-	Waiter  advancer.Waiter
+	auth        *authenticator
+
+	ctx     context.Context
+	only    set.Set[string]
+	metrics *loadMetrics
+
+	// GlobalSetup is the path to a Goatfile which is executed
+	// once before all other Goatfiles. Alongside, _setup.goat
+	// and _teardown.goat files located in passed directories
+	// are used as global setup and teardown Goatfiles.
+	//
+	// The state resulting from the global setup is passed into
+	// every executed Goatfile. The Teardown sections of the global
+	// setup Goatfiles as well as the global teardown Goatfiles are
+	// executed after all other Goatfiles, even if these failed or
+	// the execution has been canceled.
+	GlobalSetup string
+
+	// Engines maps engine names to functions initializing
+	// a new instance of the engine. A Goatfile can select
+	// one of them with the engine option in its Defaults
+	// section.
+	Engines map[string]func() engine.Engine
+
+	Dry           bool
+	NoAbort       bool
+	Skip          []string
+	Waiter        advancer.Waiter
+	Debugger      Debugger
+	GrpcRequester grpcrequester.Requester
 }
 
 // New initializes a new instance of Executor using
@@ -51,7 +78,9 @@ func New(ctx context.Context, engineMaker func() engine.Engine, req requester.Re
 	t.ctx = ctx
 	t.engineMaker = engineMaker
 	t.req = req
+	t.auth = newAuthenticator(ctx, req)
 	t.Waiter = advancer.None{}
+	t.GrpcRequester = grpcrequester.NewClient(nil)
 
 	return &t
 }
@@ -60,7 +89,28 @@ func New(ctx context.Context, engineMaker func() engine.Engine, req requester.Re
 // from the given file or directory. The given
 // initialParams are used as initial state for the
 // runtime engine.
+//
+// Before the Goatfiles are executed, the global setup
+// Goatfiles are executed once. Their resulting state is
+// passed into every executed Goatfile. See GlobalSetup
+// for more information.
 func (t *Executor) Execute(pathes []string, initialParams engine.State, showTeardownParamErrors bool) (res Result, err error) {
+	setups, teardowns, err := t.findGlobalGoatfiles(pathes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(setups) == 0 && len(teardowns) == 0 {
+		return t.execute(pathes, initialParams, showTeardownParamErrors)
+	}
+
+	return t.executeWithGlobals(setups, teardowns, initialParams, showTeardownParamErrors,
+		func(params engine.State) (Result, error) {
+			return t.execute(pathes, params, showTeardownParamErrors)
+		})
+}
+
+func (t *Executor) execute(pathes []string, initialParams engine.State, showTeardownParamErrors bool) (res Result, err error) {
 	if len(pathes) == 1 {
 		stat, err := os.Stat(pathes[0])
 		if err != nil {
@@ -91,7 +141,10 @@ func (t *Executor) ExecuteGoatfile(gf goatfile.Goatfile, initialParams engine.St
 		return Result{}, nil
 	}
 
-	eng := t.engineMaker()
+	eng, err := t.newEngineFor(gf)
+	if err != nil {
+		return Result{}, err
+	}
 	eng.SetState(initialParams)
 
 	return t.executeGoatfile(log, gf, eng, true, showTeardownParamErrors)
@@ -216,34 +269,19 @@ func (t *Executor) executeGoatfile(
 }
 
 func (t *Executor) executeFromPathes(pathes []string, initialParams engine.State, showTeardownParamErrors bool) (finalRes Result, err error) {
-	var goatfiles []goatfile.Goatfile
-
-	for _, path := range pathes {
-		err := filepath.WalkDir(path, func(path string, d fs.DirEntry, e error) error {
-			if e != nil {
-				return e
-			}
-
-			if d.IsDir() && strings.HasPrefix(d.Name(), "_") {
-				return fs.SkipDir
-			}
-			if d.IsDir() ||
-				filepath.Ext(d.Name()) != "."+goatfile.FileExtension ||
-				strings.HasPrefix(d.Name(), "_") {
-				return nil
-			}
+	goatfiles, err := t.parseGoatfiles(pathes)
+	if err != nil {
+		return Result{}, err
+	}
 
-			gf, err := t.parseGoatfile(path)
-			if err != nil {
-				return err
+	if t.only != nil {
+		filtered := goatfiles[:0]
+		for _, gf := range goatfiles {
+			if t.only.Contains(filepath.Clean(gf.Path)) {
+				filtered = append(filtered, gf)
 			}
-
-			goatfiles = append(goatfiles, gf)
-			return nil
-		})
-		if err != nil {
-			return Result{}, err
 		}
+		goatfiles = filtered
 	}
 
 	if len(goatfiles) == 0 {
@@ -291,6 +329,43 @@ func (t *Executor) executeFromPathes(pathes []string, initialParams engine.State
 	return finalRes, nil
 }
 
+// parseGoatfiles walks the given pathes and parses all
+// Goatfiles which are executed as batches. Files and
+// directories prefixed with an underscore are skipped.
+func (t *Executor) parseGoatfiles(pathes []string) ([]goatfile.Goatfile, error) {
+	var goatfiles []goatfile.Goatfile
+
+	for _, path := range pathes {
+		err := filepath.WalkDir(path, func(path string, d fs.DirEntry, e error) error {
+			if e != nil {
+				return e
+			}
+
+			if d.IsDir() && strings.HasPrefix(d.Name(), "_") {
+				return fs.SkipDir
+			}
+			if d.IsDir() ||
+				filepath.Ext(d.Name()) != "."+goatfile.FileExtension ||
+				strings.HasPrefix(d.Name(), "_") {
+				return nil
+			}
+
+			gf, err := t.parseGoatfile(path)
+			if err != nil {
+				return err
+			}
+
+			goatfiles = append(goatfiles, gf)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return goatfiles, nil
+}
+
 func (t *Executor) parseGoatfile(path string) (gf goatfile.Goatfile, err error) {
 	log.Debug().Field("from", path).Msg("Parsing goatfile ...")
 
@@ -359,7 +434,7 @@ func (t *Executor) executeAction(
 		err = t.executeRequest(eng, req, gf)
 		if err != nil {
 			res.IncFailed()
-			err = errs.WithSuffix(err, fmt.Sprintf("(%s:%d)", req.Path, req.PosLine))
+			err = withSourcePos(err, req)
 		}
 		return res, err
 
@@ -387,6 +462,8 @@ func (t *Executor) executeAction(
 func (t *Executor) executeRequest(eng engine.Engine, req *goatfile.Request, gf goatfile.Goatfile) (err error) {
 	req.Merge(gf.Defaults)
 
+	name := req.Method + " " + req.URI
+
 	if !t.isAbortOnError(req) {
 		defer func() {
 			if err != nil {
@@ -395,6 +472,28 @@ func (t *Executor) executeRequest(eng engine.Engine, req *goatfile.Request, gf g
 		}()
 	}
 
+	// The request is reported as defined if the
+	// substitution has failed.
+	var (
+		resp     Response
+		received *Response
+		reported = *req
+	)
+	defer func() {
+		if err != nil {
+			err = newRequestError(err, &reported, received)
+		}
+	}()
+
+	if loader, ok := eng.(engine.ModuleLoader); ok {
+		loader.SetModuleDir(filepath.Dir(req.Path))
+	}
+
+	err = loadTemplateFuncs(eng, req)
+	if err != nil {
+		return err
+	}
+
 	state := eng.State()
 
 	err = req.PreSubstituteWithParams(state)
@@ -410,7 +509,7 @@ func (t *Executor) executeRequest(eng engine.Engine, req *goatfile.Request, gf g
 	if preScript != "" {
 		err = eng.Run(preScript)
 		if err != nil {
-			return errs.WithPrefix("preScript failed:", err)
+			return errs.WithPrefix("preScript failed:", newScriptError(err, "PreScript", preScript, req.PreScriptPos))
 		}
 		state = eng.State()
 	}
@@ -420,6 +519,7 @@ func (t *Executor) executeRequest(eng engine.Engine, req *goatfile.Request, gf g
 		return errs.WithPrefix("failed substituting request with parameters:",
 			NewParamsParsingError(err))
 	}
+	reported = *req
 
 	execOpts := ExecOptionsFromMap(req.Options)
 	if !execOpts.Condition {
@@ -437,6 +537,14 @@ func (t *Executor) executeRequest(eng engine.Engine, req *goatfile.Request, gf g
 
 	t.Waiter.Wait()
 
+	if t.Debugger != nil {
+		if t.Debugger.BeforeRequest(req, eng) == DebugSkip {
+			log.Warn().Field("req", req).Msg("Skipped by debugger")
+			return nil
+		}
+		state = eng.State()
+	}
+
 	err = req.InsertRawDataIntoBody(state)
 	if err != nil {
 		return errs.WithPrefix("failed inserting raw variable in body:",
@@ -449,25 +557,41 @@ func (t *Executor) executeRequest(eng engine.Engine, req *goatfile.Request, gf g
 			NewParamsParsingError(err))
 	}
 
-	httpReq, err := req.ToHttpRequest()
-	if err != nil {
-		return errs.WithPrefix("failed transforming to http request:", err)
-	}
+	var latency time.Duration
+	for {
+		start := time.Now()
+		if req.IsGrpc() {
+			resp, err = t.doGrpcRequest(req)
+		} else {
+			resp, err = t.doHttpRequest(eng, req)
+		}
+		latency = time.Since(start)
 
-	if authOpts, ok := AuthOptionsFromMap(req.Auth); ok {
-		httpReq.Header.Set("Authorization", authOpts.HeaderValue())
+		if t.Debugger == nil {
+			break
+		}
+		if err == nil {
+			state.Merge(engine.State{"response": resp})
+			eng.SetState(state)
+		}
+		action := t.Debugger.AfterResponse(req, resp, err, eng)
+		state = eng.State()
+		if action != DebugResend {
+			break
+		}
 	}
 
-	reqOpts := requester.OptionsFromMap(req.Options)
-	httpResp, err := t.req.Do(httpReq, reqOpts)
-	if err != nil {
-		return errs.WithPrefix("http request failed:", err)
+	if t.metrics != nil {
+		defer func() {
+			t.metrics.record(req, name, latency, err)
+		}()
 	}
 
-	resp, err := FromHttpResponse(httpResp, req.Options)
 	if err != nil {
-		return errs.WithPrefix("response interpretation failed:", err)
+		return err
 	}
+	received = &resp
+	reported = *req
 
 	state.Merge(engine.State{"response": resp})
 	eng.SetState(state)
@@ -480,13 +604,93 @@ func (t *Executor) executeRequest(eng engine.Engine, req *goatfile.Request, gf g
 	if script != "" {
 		err = eng.Run(script)
 		if err != nil {
-			return errs.WithPrefix("script failed:", err)
+			return errs.WithPrefix("script failed:", newScriptError(err, "Script", script, req.ScriptPos))
 		}
 	}
 
 	return nil
 }
 
+func (t *Executor) doHttpRequest(eng engine.Engine, req *goatfile.Request) (Response, error) {
+	newReq := func() (*http.Request, error) {
+		httpReq, err := req.ToHttpRequest()
+		if err != nil {
+			return nil, errs.WithPrefix("failed transforming to http request:", err)
+		}
+		return httpReq, nil
+	}
+
+	reqOpts := requester.OptionsFromMap(req.Options)
+	reqOpts.TLS = resolveTLSPaths(path.Dir(req.Path), reqOpts.TLS)
+
+	// The tracer is started right before sending the request, so
+	// that acquiring auth tokens and signing the request are not
+	// part of the timings.
+	var trace *tracer
+	do := func(httpReq *http.Request) (*http.Response, error) {
+		trace = newTracer()
+		httpReq = httpReq.WithContext(httptrace.WithClientTrace(httpReq.Context(), trace.clientTrace()))
+		httpResp, err := t.req.Do(httpReq, reqOpts)
+		if err != nil {
+			return nil, errs.WithPrefix("http request failed:", err)
+		}
+		return httpResp, nil
+	}
+
+	var (
+		httpResp *http.Response
+		err      error
+	)
+	if authOpts, ok := AuthOptionsFromMap(req.Auth); ok {
+		httpResp, err = t.auth.Do(authOpts, reqOpts.TransportOptions, newReq, do)
+	} else {
+		httpResp, err = doRequest(newReq, do)
+	}
+	if err != nil {
+		return Response{}, err
+	}
+
+	resp, err := FromHttpStreamResponse(httpResp, req.Options,
+		streamPredicate(eng, StreamOptionsFromMap(req.Options).Until))
+	if err != nil {
+		return Response{}, errs.WithPrefix("response interpretation failed:", err)
+	}
+
+	resp.Timings = trace.Timings(time.Now())
+
+	return resp, nil
+}
+
+// resolveTLSPaths resolves all relative file paths in
+// the given TLS options relative to dir.
+func resolveTLSPaths(dir string, opt requester.TLSOptions) requester.TLSOptions {
+	if opt.CertFile != "" {
+		opt.CertFile = resolvePath(dir, opt.CertFile)
+	}
+	if opt.KeyFile != "" {
+		opt.KeyFile = resolvePath(dir, opt.KeyFile)
+	}
+	if len(opt.CAFiles) > 0 {
+		caFiles := make([]string, 0, len(opt.CAFiles))
+		for _, caFile := range opt.CAFiles {
+			caFiles = append(caFiles, resolvePath(dir, caFile))
+		}
+		opt.CAFiles = caFiles
+	}
+	return opt
+}
+
+func doRequest(
+	newReq func() (*http.Request, error),
+	do func(*http.Request) (*http.Response, error),
+) (*http.Response, error) {
+	req, err := newReq()
+	if err != nil {
+		return nil, err
+	}
+	return do(req)
+}
+
 func (t *Executor) executeExecute(params goatfile.Execute, eng engine.Engine, showTeardownParamErrors bool) (Result, error) {
 	pth := goatfile.Extend(path.Join(path.Dir(params.Path), params.File), goatfile.FileExtension)
 	gf, err := t.parseGoatfile(pth)
@@ -503,7 +707,10 @@ func (t *Executor) executeExecute(params goatfile.Execute, eng engine.Engine, sh
 
 	log := log.Tagged(gf.Path)
 
-	isolatedEng := t.engineMaker()
+	isolatedEng, err := t.newEngineFor(gf)
+	if err != nil {
+		return Result{}, err
+	}
 	isolatedEng.SetState(params.Params)
 
 	res, err := t.executeGoatfile(log, gf, isolatedEng, false, showTeardownParamErrors)
@@ -524,6 +731,42 @@ func (t *Executor) executeExecute(params goatfile.Execute, eng engine.Engine, sh
 	return res, nil
 }
 
+// streamPredicate returns a StreamPredicate evaluating the
+// given expression in the passed engine with the current
+// event set as 'event'. After evaluation, the previous
+// value of 'event' in the state is restored or, if there
+// was none, the event is removed from the engine. If expr
+// is empty, nil is returned.
+func streamPredicate(eng engine.Engine, expr string) StreamPredicate {
+	if expr == "" {
+		return nil
+	}
+
+	return func(ev Event) (bool, error) {
+		prev, hasPrev := eng.State()["event"]
+
+		err := eng.Set("event", ev)
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			if hasPrev {
+				eng.Set("event", prev)
+			} else {
+				eng.Unset("event")
+			}
+		}()
+
+		v, err := eng.Eval(expr)
+		if err != nil {
+			return false, err
+		}
+
+		stop, _ := v.(bool)
+		return stop, nil
+	}
+}
+
 func (t *Executor) isSkip(section goatfile.SectionName) bool {
 	for _, s := range t.Skip {
 		if strings.ToLower(s) == string(section) {
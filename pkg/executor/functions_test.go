@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+)
+
+func TestExecute_TemplateFunctions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(r.Header.Get("X-Signature") + " " + string(body)))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"lib/signing.js": `
+let calls = 0;
+exports.signPayload = (payload) => hmac("sha256", secret, JSON.stringify(payload));
+exports.counter = () => ++calls;
+`,
+		"common.goat": `
+### Defaults
+
+[Functions]
+@lib/signing.js
+`,
+		"test.goat": `
+use common
+
+### Tests
+
+POST {{.instance}}
+
+[Header]
+X-Signature: {{ signPayload .body }}
+
+[Body]
+{{ json .body }} {{ counter }}
+
+[Script]
+var first = response.BodyRaw.toString();
+
+---
+
+GET {{.instance}}
+
+[Body]
+{{ counter }}
+
+[Script]
+var second = response.BodyRaw.toString();
+`,
+		"failing.goat": `
+GET {{.instance}}
+
+[Functions]
+exports.a = () => 1;
+exports.b = ) => 2;
+`,
+	})
+
+	params := engine.State{
+		"instance": srv.URL,
+		"secret":   "key",
+		"body":     map[string]any{"id": 1},
+	}
+
+	t.Run("functions", func(t *testing.T) {
+		eng, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "test.goat"), params)
+		require.Nil(t, err)
+
+		state := eng.State()
+
+		signature, err := eng.Eval(`hmac("sha256", "key", JSON.stringify({id: 1}))`)
+		require.Nil(t, err)
+		assert.Equal(t, signature.(string)+` {"id":1} 1`+"\n", state["first"])
+		assert.Equal(t, " 2\n", state["second"])
+	})
+
+	t.Run("syntax-error", func(t *testing.T) {
+		_, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "failing.goat"), params)
+		require.NotNil(t, err)
+
+		pos, ok := errorSourcePos(err)
+		require.True(t, ok, err)
+		assert.Equal(t, 6, pos.Line)
+		assert.Contains(t, err.Error(), "loading functions failed")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := executeGoatfile(t, engine.NewExpr, filepath.Join(dir, "test.goat"), params)
+		assert.ErrorIs(t, err, ErrFunctionsNotSupported)
+	})
+}
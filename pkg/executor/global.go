@@ -0,0 +1,170 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/studio-b12/goat/pkg/clr"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/zekrotja/rogu/log"
+)
+
+const (
+	globalSetupFileName    = "_setup." + goatfile.FileExtension
+	globalTeardownFileName = "_teardown." + goatfile.FileExtension
+)
+
+// findGlobalGoatfiles returns the parsed global setup and teardown
+// Goatfiles. These are the Goatfile passed as GlobalSetup as well
+// as the _setup.goat and _teardown.goat files located directly in
+// the given directories.
+func (t *Executor) findGlobalGoatfiles(pathes []string) (setups, teardowns []goatfile.Goatfile, err error) {
+	var setupPathes, teardownPathes []string
+
+	if t.GlobalSetup != "" {
+		setupPathes = append(setupPathes, filepath.Clean(t.GlobalSetup))
+	}
+
+	for _, path := range pathes {
+		stat, err := os.Stat(path)
+		if err != nil || !stat.IsDir() {
+			continue
+		}
+
+		setupPath := filepath.Join(path, globalSetupFileName)
+		if fileExists(setupPath) && !contains(setupPathes, setupPath) {
+			setupPathes = append(setupPathes, setupPath)
+		}
+
+		teardownPath := filepath.Join(path, globalTeardownFileName)
+		if fileExists(teardownPath) && !contains(teardownPathes, teardownPath) {
+			teardownPathes = append(teardownPathes, teardownPath)
+		}
+	}
+
+	for _, path := range setupPathes {
+		gf, err := t.parseGoatfile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		setups = append(setups, gf)
+	}
+
+	for _, path := range teardownPathes {
+		gf, err := t.parseGoatfile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		teardowns = append(teardowns, gf)
+	}
+
+	return setups, teardowns, nil
+}
+
+// executeWithGlobals executes the given global setup Goatfiles in a
+// shared engine before calling execute with the resulting state merged
+// into initialParams. The Teardown sections of the setup Goatfiles as
+// well as the given teardown Goatfiles are executed afterwards in any
+// case, even if the setup or execute have failed or the execution has
+// been canceled.
+func (t *Executor) executeWithGlobals(
+	setups, teardowns []goatfile.Goatfile,
+	initialParams engine.State,
+	showTeardownParamErrors bool,
+	execute func(params engine.State) (Result, error),
+) (res Result, err error) {
+	if t.Dry {
+		for _, gf := range append(setups, teardowns...) {
+			log.Debug().Msg("Parsed global Goatfile\n" + gf.String())
+		}
+		return execute(initialParams)
+	}
+
+	eng := t.newEngine()
+	eng.SetState(initialParams)
+
+	// Teardown steps are collected in reverse order so that the
+	// teardown of the first setup Goatfile is executed last.
+	var globalTeardowns []goatfile.Goatfile
+	for i := len(teardowns) - 1; i >= 0; i-- {
+		globalTeardowns = append(globalTeardowns, asTeardown(teardowns[i]))
+	}
+
+	defer func() {
+		for i := len(globalTeardowns) - 1; i >= 0; i-- {
+			gf := globalTeardowns[i]
+			if len(gf.Teardown) == 0 {
+				continue
+			}
+
+			log.Info().Field("path", gf.Path).Msg(clr.Print(clr.Format("Executing global teardown ...", clr.ColorFGPurple, clr.FormatBold)))
+
+			tRes, tErr := t.executeGoatfile(log.Tagged(gf.Path), gf, eng, true, showTeardownParamErrors)
+			res.Merge(tRes)
+			if tErr != nil {
+				log.Error().Err(tErr).Field("path", gf.Path).Msg(clr.Print(clr.Format("Global teardown failed", clr.ColorFGRed, clr.FormatBold)))
+				err = errs.Join(err, errs.WithPrefix(fmt.Sprintf("global teardown %s failed:", gf.Path), tErr))
+			}
+		}
+	}()
+
+	for _, gf := range setups {
+		setupGf := gf
+		setupGf.Teardown = nil
+
+		teardownGf := gf
+		teardownGf.Setup = nil
+		teardownGf.Tests = nil
+		globalTeardowns = append(globalTeardowns, teardownGf)
+
+		log.Info().Field("path", gf.Path).Msg(clr.Print(clr.Format("Executing global setup ...", clr.ColorFGPurple, clr.FormatBold)))
+
+		sRes, sErr := t.executeGoatfile(log.Tagged(gf.Path), setupGf, eng, true, showTeardownParamErrors)
+		res.Merge(sRes)
+		if sErr != nil {
+			log.Error().Err(sErr).Field("path", gf.Path).Msg(clr.Print(clr.Format("Global setup failed", clr.ColorFGRed, clr.FormatBold)))
+			return res, errs.WithPrefix(fmt.Sprintf("global setup %s failed:", gf.Path), sErr)
+		}
+	}
+
+	params := eng.State()
+	delete(params, "response")
+
+	execRes, err := execute(params)
+	res.Merge(execRes)
+
+	return res, err
+}
+
+// asTeardown returns a copy of the given Goatfile where all
+// actions are moved into the Teardown section so that they
+// are executed regardless of previous failures.
+func asTeardown(gf goatfile.Goatfile) goatfile.Goatfile {
+	actions := make([]goatfile.Action, 0, len(gf.Setup)+len(gf.Tests)+len(gf.Teardown))
+	actions = append(actions, gf.Setup...)
+	actions = append(actions, gf.Tests...)
+	actions = append(actions, gf.Teardown...)
+
+	gf.Setup = nil
+	gf.Tests = nil
+	gf.Teardown = actions
+
+	return gf
+}
+
+func fileExists(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && !stat.IsDir()
+}
+
+func contains(list []string, v string) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestExecute_GlobalSetup(t *testing.T) {
+	var (
+		mtx   sync.Mutex
+		calls []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		calls = append(calls, r.URL.Path)
+		mtx.Unlock()
+
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`"token-123"`))
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			if _, err := r.Cookie("session"); err != nil || r.Header.Get("Authorization") != "token-123" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	const batch = `
+GET {{.instance}}/%s
+
+[Header]
+Authorization: {{.token}}
+
+[Script]
+assert(response.StatusCode === 200, "status " + response.StatusCode);
+`
+
+	newExecutor := func() *Executor {
+		return New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+	}
+
+	params := func() engine.State {
+		return engine.State{"instance": srv.URL}
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		calls = nil
+		dir := t.TempDir()
+
+		writeFiles(t, dir, map[string]string{
+			"_setup.goat": `
+GET {{.instance}}/login
+
+[Script]
+var token = response.Body;
+
+### Teardown
+
+GET {{.instance}}/logout
+`,
+			"_teardown.goat": `
+GET {{.instance}}/cleanup
+`,
+			"a.goat":     fmt.Sprintf(batch, "a"),
+			"sub/b.goat": fmt.Sprintf(batch, "b"),
+		})
+
+		_, err := newExecutor().Execute([]string{dir}, params(), true)
+		require.Nil(t, err)
+
+		assert.Equal(t, []string{"/login", "/a", "/b", "/logout", "/cleanup"}, calls)
+	})
+
+	t.Run("teardown-after-failure", func(t *testing.T) {
+		calls = nil
+		dir := t.TempDir()
+
+		writeFiles(t, dir, map[string]string{
+			"_setup.goat": `
+GET {{.instance}}/login
+
+[Script]
+var token = response.Body;
+
+### Teardown
+
+GET {{.instance}}/logout
+`,
+			"a.goat": fmt.Sprintf(batch, "fail"),
+		})
+
+		_, err := newExecutor().Execute([]string{dir}, params(), true)
+		require.Error(t, err)
+
+		_, ok := err.(*BatchResultError)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"/login", "/fail", "/logout"}, calls)
+	})
+
+	t.Run("setup-failure", func(t *testing.T) {
+		calls = nil
+		dir := t.TempDir()
+
+		writeFiles(t, dir, map[string]string{
+			"_setup.goat": `
+GET {{.instance}}/fail
+
+[Script]
+assert(response.StatusCode === 200);
+
+### Teardown
+
+GET {{.instance}}/logout
+`,
+			"a.goat": fmt.Sprintf(batch, "a"),
+		})
+
+		_, err := newExecutor().Execute([]string{dir}, params(), true)
+		require.Error(t, err)
+
+		assert.Equal(t, []string{"/fail", "/logout"}, calls)
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		calls = nil
+		dir := t.TempDir()
+
+		writeFiles(t, dir, map[string]string{
+			"login.goat": `
+GET {{.instance}}/login
+
+[Script]
+var token = response.Body;
+`,
+			"tests/a.goat": fmt.Sprintf(batch, "a"),
+		})
+
+		exec := newExecutor()
+		exec.GlobalSetup = filepath.Join(dir, "login.goat")
+
+		_, err := exec.Execute([]string{filepath.Join(dir, "tests", "a.goat")}, params(), true)
+		require.Nil(t, err)
+
+		assert.Equal(t, []string{"/login", "/a"}, calls)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		calls = nil
+		dir := t.TempDir()
+
+		writeFiles(t, dir, map[string]string{
+			"_setup.goat": `
+GET {{.instance}}/login
+
+[Script]
+var token = response.Body;
+`,
+			"_teardown.goat": `
+GET {{.instance}}/cleanup
+`,
+			"a.goat": fmt.Sprintf(batch, "a"),
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		exec := New(ctx, engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+		cancel()
+
+		_, err := exec.Execute([]string{dir}, params(), true)
+		require.Error(t, err)
+
+		assert.Equal(t, []string{"/cleanup"}, calls)
+	})
+}
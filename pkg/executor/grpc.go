@@ -0,0 +1,152 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/studio-b12/goat/pkg/grpcrequester"
+	"github.com/studio-b12/goat/pkg/util"
+)
+
+func (t *Executor) doGrpcRequest(req *goatfile.Request) (Response, error) {
+	grpcReq, err := toGrpcRequest(req)
+	if err != nil {
+		return Response{}, errs.WithPrefix("failed transforming to grpc request:", err)
+	}
+
+	if authOpts, ok := AuthOptionsFromMap(req.Auth); ok {
+		authValue, err := t.auth.HeaderValue(authOpts)
+		if err != nil {
+			return Response{}, errs.WithPrefix("failed applying auth:", err)
+		}
+		grpcReq.Header.Set("Authorization", authValue)
+	}
+
+	opts := grpcrequester.OptionsFromMap(req.Options)
+	opts.ImportPaths = resolveImportPaths(path.Dir(req.Path), opts.ImportPaths)
+
+	start := time.Now()
+	grpcResp, err := t.GrpcRequester.Invoke(t.ctx, grpcReq, opts)
+	if err != nil {
+		return Response{}, errs.WithPrefix("grpc request failed:", err)
+	}
+
+	resp, err := FromGrpcResponse(grpcResp)
+	if err != nil {
+		return Response{}, errs.WithPrefix("response interpretation failed:", err)
+	}
+	resp.Timings.Total = milliseconds(time.Since(start))
+
+	return resp, nil
+}
+
+// toGrpcRequest returns a grpcrequester.Request built
+// from the given Request.
+//
+// The URI must be in the format '[scheme://]host:port/package.Service/Method',
+// where the optional scheme is either 'grpc' or 'grpcs'. When 'grpcs' is used,
+// the connection is secured using TLS.
+func toGrpcRequest(req *goatfile.Request) (grpcrequester.Request, error) {
+	var grpcReq grpcrequester.Request
+
+	uri := req.URI
+	if scheme, rest, ok := strings.Cut(uri, "://"); ok {
+		switch strings.ToLower(scheme) {
+		case "grpc":
+		case "grpcs":
+			grpcReq.Secure = true
+		default:
+			return grpcrequester.Request{}, fmt.Errorf("invalid grpc URI scheme: %s", scheme)
+		}
+		uri = rest
+	}
+
+	target, method, ok := strings.Cut(uri, "/")
+	if !ok || target == "" {
+		return grpcrequester.Request{}, ErrInvalidGrpcURI
+	}
+
+	grpcReq.Target = target
+	grpcReq.Method = method
+	// The header is cloned so that headers set on the
+	// gRPC request, like the Authorization header, are
+	// not applied to the parsed request.
+	grpcReq.Header = req.Header.Clone()
+	if grpcReq.Header == nil {
+		grpcReq.Header = http.Header{}
+	}
+
+	body, err := util.ReadReaderToString(req.Body.Reader())
+	if err != nil {
+		return grpcrequester.Request{}, errs.WithPrefix("failed reading body data:", err)
+	}
+	grpcReq.Body = []byte(body)
+
+	return grpcReq, nil
+}
+
+// FromGrpcResponse builds a Response from the given
+// gRPC Response reference.
+//
+// The gRPC status code is set as StatusCode. The
+// response message of unary calls is set as Body.
+// Messages received from server streaming calls
+// are set as Events.
+func FromGrpcResponse(resp *grpcrequester.Response) (Response, error) {
+	var r Response
+
+	r.StatusCode = resp.Code
+	r.Status = resp.Status
+	if resp.Message != "" {
+		r.Status = fmt.Sprintf("%s: %s", resp.Status, resp.Message)
+	}
+	r.Proto = "gRPC"
+	r.Header = resp.Header
+	r.Trailer = resp.Trailer
+
+	if len(resp.Messages) == 0 {
+		return r, nil
+	}
+
+	if !resp.Streaming {
+		r.BodyRaw = resp.Messages[0]
+		r.ContentLength = int64(len(r.BodyRaw))
+		return r, json.Unmarshal(r.BodyRaw, &r.Body)
+	}
+
+	r.BodyRaw = bytes.Join(resp.Messages, []byte("\n"))
+	r.ContentLength = int64(len(r.BodyRaw))
+	r.Events = make([]Event, 0, len(resp.Messages))
+	for _, msg := range resp.Messages {
+		ev := Event{Data: string(msg)}
+		if err := json.Unmarshal(msg, &ev.Body); err != nil {
+			return Response{}, err
+		}
+		r.Events = append(r.Events, ev)
+	}
+
+	return r, nil
+}
+
+// resolveImportPaths resolves the given import paths relative to
+// the given directory. If no import paths are given, the directory
+// itself is returned as only import path.
+func resolveImportPaths(dir string, importPaths []string) []string {
+	if len(importPaths) == 0 {
+		return []string{dir}
+	}
+
+	resolved := make([]string, 0, len(importPaths))
+	for _, p := range importPaths {
+		resolved = append(resolved, resolvePath(dir, p))
+	}
+
+	return resolved
+}
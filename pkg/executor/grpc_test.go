@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+func TestToGrpcRequest(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		req := &goatfile.Request{}
+		req.URI = "localhost:50051/foo.v1.Bar/Bazz"
+		req.Method = "GRPC"
+		req.Header = http.Header{"X-Foo": {"bar"}}
+		req.Body = goatfile.StringContent(`{"foo":"bar"}`)
+
+		grpcReq, err := toGrpcRequest(req)
+		assert.Nil(t, err, err)
+		assert.Equal(t, "localhost:50051", grpcReq.Target)
+		assert.Equal(t, "foo.v1.Bar/Bazz", grpcReq.Method)
+		assert.False(t, grpcReq.Secure)
+		assert.Equal(t, []byte(`{"foo":"bar"}`), grpcReq.Body)
+		assert.Equal(t, http.Header{"X-Foo": []string{"bar"}}, grpcReq.Header)
+
+		grpcReq.Header.Set("Authorization", "Bearer secret")
+		assert.Equal(t, http.Header{"X-Foo": []string{"bar"}}, req.Header)
+	})
+
+	t.Run("secure", func(t *testing.T) {
+		req := &goatfile.Request{Body: goatfile.NoContent{}}
+		req.URI = "grpcs://example.com:443/foo.v1.Bar/Bazz"
+		req.Method = "grpc"
+
+		grpcReq, err := toGrpcRequest(req)
+		assert.Nil(t, err, err)
+		assert.Equal(t, "example.com:443", grpcReq.Target)
+		assert.Equal(t, "foo.v1.Bar/Bazz", grpcReq.Method)
+		assert.True(t, grpcReq.Secure)
+		assert.Empty(t, grpcReq.Body)
+		assert.NotNil(t, grpcReq.Header)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		req := &goatfile.Request{Body: goatfile.NoContent{}}
+		req.URI = "http://example.com/foo.v1.Bar/Bazz"
+		_, err := toGrpcRequest(req)
+		assert.Error(t, err)
+
+		req.URI = "localhost:50051"
+		_, err = toGrpcRequest(req)
+		assert.ErrorIs(t, err, ErrInvalidGrpcURI)
+	})
+}
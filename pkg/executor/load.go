@@ -0,0 +1,439 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/studio-b12/goat/pkg/requester"
+	"github.com/zekrotja/rogu/log"
+)
+
+var (
+	ErrInvalidVirtualUsers = errors.New("the number of virtual users must be at least 1")
+	ErrInvalidArrivalRate  = errors.New("the arrival rate must not be negative")
+)
+
+// LoadOptions configures a load test executed with Load.
+type LoadOptions struct {
+	// VirtualUsers is the number of virtual users
+	// executing iterations concurrently.
+	VirtualUsers int
+
+	// Duration is the maximum duration of the load
+	// test. If zero, the load test runs until the
+	// number of Iterations has been executed.
+	Duration time.Duration
+
+	// Iterations is the total number of iterations
+	// executed by all virtual users. If zero and no
+	// Duration is given, every virtual user executes
+	// one iteration.
+	Iterations int
+
+	// ArrivalRate is the number of iterations started
+	// per second. If all virtual users are busy when
+	// an iteration is due, it is dropped. If zero,
+	// every virtual user starts the next iteration
+	// right after the previous one has finished.
+	ArrivalRate float64
+
+	// RampUp is the duration over which the start
+	// of the virtual users is evenly distributed.
+	RampUp time.Duration
+}
+
+// LoadReport contains the results of a load test.
+type LoadReport struct {
+	VirtualUsers      int
+	Duration          time.Duration
+	Iterations        int
+	FailedIterations  int
+	DroppedIterations int
+	Requests          []RequestStats
+}
+
+// RequestStats contains the statistics of a
+// single request executed during a load test.
+type RequestStats struct {
+	Name string
+	Path string
+	Line int
+
+	Count      int
+	Failed     int
+	Throughput float64
+	ErrorRate  float64
+
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// Load executes the Goatfile at the given path as load test. Every
+// virtual user executes the Setup section once, then the Tests section
+// repeatedly according to the given options and the Teardown section
+// at the end. Each virtual user has its own state and, if supported by
+// the Requester, its own set of cookie jars.
+func (t *Executor) Load(path string, initialParams engine.State, opts LoadOptions) (report LoadReport, err error) {
+	if opts.VirtualUsers < 1 {
+		return LoadReport{}, ErrInvalidVirtualUsers
+	}
+	if opts.ArrivalRate < 0 {
+		return LoadReport{}, ErrInvalidArrivalRate
+	}
+	if opts.Duration <= 0 && opts.Iterations <= 0 {
+		opts.Iterations = opts.VirtualUsers
+	}
+
+	// The Goatfile is parsed once and copied for every
+	// execution, so that syntax errors are reported before
+	// starting.
+	gf, err := t.parseGoatfile(path)
+	if err != nil {
+		return LoadReport{}, err
+	}
+
+	ctx := t.ctx
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	metrics := newLoadMetrics()
+	cIterations := make(chan struct{})
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < opts.VirtualUsers; i++ {
+		delay := opts.RampUp * time.Duration(i) / time.Duration(opts.VirtualUsers)
+		vu := t.newVirtualUser(ctx, metrics)
+
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			vu.runVirtualUser(ctx, id, delay, gf, initialParams, cIterations, metrics)
+		}(i)
+	}
+
+	// When all virtual users have exited, for example because
+	// their setup has failed, no further iterations are dispatched.
+	cVUsDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(cVUsDone)
+	}()
+
+	started, dropped := dispatchIterations(ctx, opts, cIterations, cVUsDone)
+	<-cVUsDone
+
+	report = metrics.report(time.Since(start))
+	report.VirtualUsers = opts.VirtualUsers
+	report.DroppedIterations = dropped
+
+	log.Debug().Field("started", started).Msg("Load test finished")
+
+	return report, nil
+}
+
+// dispatchIterations sends iterations to cIterations until the number
+// of iterations has been reached, ctx is done or cVUsDone is closed. If
+// an arrival rate is set, iterations are only dispatched at the given
+// rate and are dropped when no virtual user is available. cIterations
+// is closed afterwards.
+func dispatchIterations(
+	ctx context.Context,
+	opts LoadOptions,
+	cIterations chan<- struct{},
+	cVUsDone <-chan struct{},
+) (started, dropped int) {
+	defer close(cIterations)
+
+	done := func() bool {
+		return opts.Iterations > 0 && started+dropped >= opts.Iterations
+	}
+
+	if opts.ArrivalRate == 0 {
+		for !done() {
+			select {
+			case cIterations <- struct{}{}:
+				started++
+			case <-ctx.Done():
+				return started, dropped
+			case <-cVUsDone:
+				return started, dropped
+			}
+		}
+		return started, dropped
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.ArrivalRate))
+	defer ticker.Stop()
+
+	for !done() {
+		select {
+		case <-ticker.C:
+			select {
+			case cIterations <- struct{}{}:
+				started++
+			default:
+				dropped++
+			}
+		case <-ctx.Done():
+			return started, dropped
+		case <-cVUsDone:
+			return started, dropped
+		}
+	}
+
+	return started, dropped
+}
+
+// newVirtualUser returns a new Executor for a virtual user which
+// records its requests to metrics. If the Requester supports it,
+// the virtual user gets its own session.
+func (t *Executor) newVirtualUser(ctx context.Context, metrics *loadMetrics) *Executor {
+	req := t.req
+	if sessions, ok := t.req.(requester.Sessions); ok {
+		req = sessions.NewSession()
+	}
+
+	vu := New(ctx, t.engineMaker, req)
+	vu.Engines = t.Engines
+	vu.NoAbort = t.NoAbort
+	vu.Skip = t.Skip
+	vu.GrpcRequester = t.GrpcRequester
+	vu.metrics = metrics
+
+	return vu
+}
+
+func (t *Executor) runVirtualUser(
+	ctx context.Context,
+	id int,
+	delay time.Duration,
+	gf goatfile.Goatfile,
+	initialParams engine.State,
+	cIterations <-chan struct{},
+	metrics *loadMetrics,
+) {
+	log := log.Tagged(fmt.Sprintf("vu-%d", id))
+
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return
+	}
+
+	// The Goatfile is copied for every execution
+	// because requests are substituted in place.
+	section := func(name goatfile.SectionName) goatfile.Goatfile {
+		sect := gf
+		if name != goatfile.SectionSetup {
+			sect.Setup = nil
+		}
+		if name != goatfile.SectionTests {
+			sect.Tests = nil
+		}
+		if name != goatfile.SectionTeardown {
+			sect.Teardown = nil
+		}
+		return sect.Copy()
+	}
+
+	eng, err := t.newEngineFor(gf)
+	if err != nil {
+		log.Error().Err(err).Msg("Setup failed")
+		return
+	}
+	eng.SetState(initialParams)
+	eng.Set("vu", id)
+
+	defer func() {
+		_, err := t.executeGoatfile(log, section(goatfile.SectionTeardown), eng, false, false)
+		if err != nil {
+			log.Error().Err(err).Msg("Teardown failed")
+		}
+	}()
+
+	_, err = t.executeGoatfile(log, section(goatfile.SectionSetup), eng, false, false)
+	if err != nil {
+		if !errors.Is(err, ErrCanceled) {
+			log.Error().Err(err).Msg("Setup failed")
+		}
+		return
+	}
+
+	for range cIterations {
+		_, err := t.executeGoatfile(log, section(goatfile.SectionTests), eng, false, false)
+		if errors.Is(err, ErrCanceled) {
+			return
+		}
+		metrics.iteration(err)
+	}
+}
+
+// loadMetrics collects the latencies and errors
+// of the requests executed during a load test.
+type loadMetrics struct {
+	mtx sync.Mutex
+
+	iterations       int
+	failedIterations int
+
+	requests map[requestKey]*requestSamples
+}
+
+type requestKey struct {
+	Name string
+	Path string
+	Line int
+}
+
+type requestSamples struct {
+	latencies []time.Duration
+	failed    int
+}
+
+func newLoadMetrics() *loadMetrics {
+	return &loadMetrics{
+		requests: make(map[requestKey]*requestSamples),
+	}
+}
+
+func (t *loadMetrics) record(req *goatfile.Request, name string, latency time.Duration, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	key := requestKey{Name: name, Path: req.Path, Line: req.PosLine}
+	samples, ok := t.requests[key]
+	if !ok {
+		samples = new(requestSamples)
+		t.requests[key] = samples
+	}
+
+	samples.latencies = append(samples.latencies, latency)
+	if err != nil {
+		samples.failed++
+	}
+}
+
+func (t *loadMetrics) iteration(err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.iterations++
+	if err != nil {
+		t.failedIterations++
+	}
+}
+
+func (t *loadMetrics) report(duration time.Duration) (report LoadReport) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	report.Duration = duration
+	report.Iterations = t.iterations
+	report.FailedIterations = t.failedIterations
+
+	for key, samples := range t.requests {
+		report.Requests = append(report.Requests, samples.stats(key, duration))
+	}
+
+	sort.Slice(report.Requests, func(i, j int) bool {
+		a, b := report.Requests[i], report.Requests[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+
+	return report
+}
+
+func (t *requestSamples) stats(key requestKey, duration time.Duration) (stats RequestStats) {
+	stats.Name = key.Name
+	stats.Path = key.Path
+	stats.Line = key.Line
+
+	stats.Count = len(t.latencies)
+	stats.Failed = t.failed
+	if stats.Count == 0 {
+		return stats
+	}
+
+	stats.Throughput = float64(stats.Count) / duration.Seconds()
+	stats.ErrorRate = float64(stats.Failed) / float64(stats.Count)
+
+	latencies := make([]time.Duration, len(t.latencies))
+	copy(latencies, t.latencies)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+
+	stats.Min = latencies[0]
+	stats.Max = latencies[len(latencies)-1]
+	stats.Mean = sum / time.Duration(len(latencies))
+	stats.P50 = percentile(latencies, 50)
+	stats.P90 = percentile(latencies, 90)
+	stats.P95 = percentile(latencies, 95)
+	stats.P99 = percentile(latencies, 99)
+
+	return stats
+}
+
+// percentile returns the p-th percentile of the given
+// sorted values using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Write writes the report as table to w.
+func (t LoadReport) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Virtual users:\t%d\n", t.VirtualUsers)
+	fmt.Fprintf(tw, "Duration:\t%s\n", t.Duration.Round(time.Millisecond))
+	fmt.Fprintf(tw, "Iterations:\t%d (%d failed, %d dropped)\n",
+		t.Iterations, t.FailedIterations, t.DroppedIterations)
+	fmt.Fprintf(tw, "Throughput:\t%.2f iterations/s\n", float64(t.Iterations)/t.Duration.Seconds())
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "REQUEST\tCOUNT\tRPS\tERRORS\tMIN\tMEAN\tP50\tP90\tP95\tP99\tMAX")
+	for _, r := range t.Requests {
+		fmt.Fprintf(tw, "%s (%s:%d)\t%d\t%.2f\t%.2f%%\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Name, r.Path, r.Line, r.Count, r.Throughput, r.ErrorRate*100,
+			formatLatency(r.Min), formatLatency(r.Mean), formatLatency(r.P50), formatLatency(r.P90),
+			formatLatency(r.P95), formatLatency(r.P99), formatLatency(r.Max))
+	}
+
+	return tw.Flush()
+}
+
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", milliseconds(d))
+}
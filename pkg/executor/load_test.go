@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestLoad(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		sessions = map[string]int{}
+		logins   atomic.Int32
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			id := logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: fmt.Sprint(id), Path: "/"})
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			c, err := r.Cookie("session")
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			mtx.Lock()
+			sessions[c.Value]++
+			mtx.Unlock()
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"load.goat": `
+### Setup
+
+POST {{.instance}}/login
+
+### Tests
+
+GET {{.instance}}/items
+
+[Script]
+assert(response.StatusCode === 200);
+
+---
+
+GET {{.instance}}/fail
+
+[Options]
+noabort = true
+
+[Script]
+assert(response.StatusCode === 200);
+`,
+	})
+
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+
+	report, err := exec.Load(filepath.Join(dir, "load.goat"), engine.State{"instance": srv.URL}, LoadOptions{
+		VirtualUsers: 3,
+		Iterations:   12,
+	})
+	require.Nil(t, err)
+
+	assert.Equal(t, 3, report.VirtualUsers)
+	assert.Equal(t, 12, report.Iterations)
+	assert.Equal(t, 12, report.FailedIterations)
+	assert.Equal(t, 0, report.DroppedIterations)
+
+	// Every virtual user has its own cookie jar.
+	assert.Equal(t, int32(3), logins.Load())
+	assert.Len(t, sessions, 3)
+	total := 0
+	for _, n := range sessions {
+		total += n
+	}
+	assert.Equal(t, 12, total)
+
+	require.Len(t, report.Requests, 3)
+
+	login := report.Requests[0]
+	assert.Equal(t, "POST {{.instance}}/login", login.Name)
+	assert.Equal(t, 3, login.Count)
+	assert.Equal(t, 0, login.Failed)
+
+	items := report.Requests[1]
+	assert.Equal(t, "GET {{.instance}}/items", items.Name)
+	assert.Equal(t, 12, items.Count)
+	assert.Equal(t, 0.0, items.ErrorRate)
+	assert.True(t, items.Min <= items.P50 && items.P50 <= items.P99 && items.P99 <= items.Max)
+
+	fail := report.Requests[2]
+	assert.Equal(t, 12, fail.Count)
+	assert.Equal(t, 1.0, fail.ErrorRate)
+}
+
+func TestLoad_ParseOnce(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "load.goat")
+
+	var (
+		mtx   sync.Mutex
+		paths []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		paths = append(paths, r.URL.Path)
+
+		// Changes to the Goatfile after the start of
+		// the load test are not picked up.
+		require.Nil(t, os.WriteFile(path, []byte("invalid"), 0644))
+	}))
+	defer srv.Close()
+
+	writeFiles(t, dir, map[string]string{
+		"load.goat": `
+### Tests
+
+GET {{.instance}}/items/{{.n}}
+
+[PreScript]
+var n = (this.n || 0) + 1;
+
+### Teardown
+
+DELETE {{.instance}}/items
+`,
+	})
+
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+
+	report, err := exec.Load(path, engine.State{"instance": srv.URL}, LoadOptions{
+		VirtualUsers: 1,
+		Iterations:   3,
+	})
+	require.Nil(t, err)
+
+	assert.Equal(t, 0, report.FailedIterations)
+	assert.Equal(t, []string{"/items/1", "/items/2", "/items/3", "/items"}, paths)
+}
+
+func TestLoad_Duration(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"load.goat": "GET {{.instance}}/items\n"})
+
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+
+	report, err := exec.Load(filepath.Join(dir, "load.goat"), engine.State{"instance": srv.URL}, LoadOptions{
+		VirtualUsers: 2,
+		Duration:     200 * time.Millisecond,
+		ArrivalRate:  50,
+	})
+	require.Nil(t, err)
+
+	assert.InDelta(t, 10, report.Iterations+report.DroppedIterations, 3)
+	assert.Less(t, report.Duration, time.Second)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+
+	_, err := exec.Load("load.goat", nil, LoadOptions{})
+	assert.ErrorIs(t, err, ErrInvalidVirtualUsers)
+
+	_, err = exec.Load("load.goat", nil, LoadOptions{VirtualUsers: 1, ArrivalRate: -1})
+	assert.ErrorIs(t, err, ErrInvalidArrivalRate)
+}
+
+func TestPercentile(t *testing.T) {
+	values := make([]time.Duration, 100)
+	for i := range values {
+		values[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	assert.Equal(t, 50*time.Millisecond, percentile(values, 50))
+	assert.Equal(t, 95*time.Millisecond, percentile(values, 95))
+	assert.Equal(t, 100*time.Millisecond, percentile(values, 100))
+	assert.Equal(t, 1*time.Millisecond, percentile(values, 0))
+	assert.Equal(t, time.Duration(0), percentile(nil, 50))
+}
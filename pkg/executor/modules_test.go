@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestExecute_Modules(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"lib/helpers.js": `
+var counter = require("./counter");
+counter.loaded++;
+exports.double = function (v) { return v * 2; };
+`,
+		"lib/counter.js": `exports.loaded = 0;`,
+		"lib/data.json":  `{"name": "goat"}`,
+		"shared/status.js": `
+exports.okStatus = 200;
+exports.expectStatus = function (response, status) {
+  assert(response.StatusCode === status, "status was " + response.StatusCode);
+};
+`,
+		"tests/test.goat": `
+GET {{.instance}}/a
+
+[Script]
+var helpers = require("../lib/helpers.js");
+require("../lib/helpers");
+var doubled = helpers.double(21);
+var loaded = require("../lib/counter").loaded;
+var name = require("../lib/data.json").name;
+expectStatus(response, 404);
+
+---
+
+GET {{.instance}}/b
+
+[Script]
+expectStatus(response, 200);
+`,
+	})
+
+	modules := engine.NewModules()
+	require.Nil(t, modules.SetLibDir(filepath.Join(dir, "shared")))
+
+	eng, err := executeGoatfile(t, func() engine.Engine {
+		return engine.NewGojaWithOptions(engine.GojaOptions{
+			Modules: modules,
+			Files:   engine.FileAccess{Root: dir},
+		})
+	}, filepath.Join(dir, "tests", "test.goat"), engine.State{"instance": srv.URL})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(),
+		filepath.Join(dir, "tests", "test.goat")+":17:13: script failed: assertion failed: status was 404")
+
+	state := eng.State()
+	assert.EqualValues(t, 42, state["doubled"])
+	assert.EqualValues(t, 1, state["loaded"])
+	assert.Equal(t, "goat", state["name"])
+	assert.NotContains(t, state, "okStatus")
+}
+
+func TestExecute_ModulesNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.goat": `
+GET {{.instance}}
+
+[Script]
+require("helpers");
+`,
+	})
+
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+	_, err := exec.Execute([]string{filepath.Join(dir, "test.goat")},
+		engine.State{"instance": srv.URL}, false)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "module not found (helpers)")
+}
+
+func TestExecute_ModulesOutsideRoot(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	libDir := t.TempDir()
+	writeFiles(t, libDir, map[string]string{
+		"status.js": `exports.okStatus = 200;`,
+	})
+	writeFiles(t, dir, map[string]string{
+		"lib/helpers.js": `exports.double = function (v) { return v * 2; };`,
+		"tests/test.goat": `
+GET {{.instance}}
+
+[Script]
+var status = require("status").okStatus;
+var doubled = require("../lib/helpers").double(21);
+`,
+	})
+
+	modules := engine.NewModules()
+	require.Nil(t, modules.SetLibDir(libDir))
+
+	execute := func(files engine.FileAccess) (engine.Engine, error) {
+		return executeGoatfile(t, func() engine.Engine {
+			return engine.NewGojaWithOptions(engine.GojaOptions{Modules: modules, Files: files})
+		}, filepath.Join(dir, "tests", "test.goat"), engine.State{"instance": srv.URL})
+	}
+
+	// Without a project root, modules can only be loaded from
+	// the directory of the Goatfile and the script library.
+	eng, err := execute(engine.FileAccess{})
+	assert.ErrorContains(t, err, engine.ErrFileAccessDenied.Error())
+	assert.EqualValues(t, 200, eng.State()["status"])
+
+	eng, err = execute(engine.FileAccess{Unrestricted: true})
+	require.Nil(t, err)
+	assert.EqualValues(t, 42, eng.State()["doubled"])
+}
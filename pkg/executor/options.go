@@ -3,6 +3,7 @@ package executor
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -63,18 +64,110 @@ func ExecOptionsFromMap(m map[string]any) ExecOptions {
 	return opt
 }
 
+// StreamOptions wraps options that control how
+// streamed responses are read.
+type StreamOptions struct {
+	MaxEvents int
+	Timeout   time.Duration
+	Until     string
+}
+
+// StreamOptionsFromMap returns a new instance of
+// StreamOptions extracted from the passed map.
+func StreamOptionsFromMap(m map[string]any) StreamOptions {
+	var opt StreamOptions
+
+	switch v := m["maxevents"].(type) {
+	case int:
+		opt.MaxEvents = v
+	case int64:
+		opt.MaxEvents = int(v)
+	}
+
+	v, ok := m["streamtimeout"]
+	if ok {
+		switch vt := v.(type) {
+		case int:
+			opt.Timeout = time.Duration(vt) * time.Millisecond
+		case int64:
+			opt.Timeout = time.Duration(vt) * time.Millisecond
+		case string:
+			opt.Timeout, _ = time.ParseDuration(vt)
+		}
+	}
+
+	if v, ok := m["streamuntil"].(string); ok {
+		opt.Until = v
+	}
+
+	return opt
+}
+
+// AuthType describes the type of authentication
+// applied to a request.
+type AuthType string
+
+const (
+	AuthTypeOAuth2 = AuthType("oauth2")
+	AuthTypeDigest = AuthType("digest")
+	AuthTypeApiKey = AuthType("apikey")
+	AuthTypeSigV4  = AuthType("sigv4")
+	AuthTypeHmac   = AuthType("hmac")
+)
+
+// OAuth2 grant types supported by the
+// oauth2 auth type.
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+)
+
+// AuthOptions wraps the options of the
+// [Auth] block of a request.
 type AuthOptions struct {
 	Type     string
 	UserName string
 	Password string
 	Token    string
+
+	// OAuth2 options
+	TokenURL     string
+	Grant        string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+
+	// API key options
+	In   string
+	Name string
+
+	// Signing options
+	AccessKey       string
+	SecretKey       string
+	SessionToken    string
+	Region          string
+	Service         string
+	Algorithm       string
+	Encoding        string
+	Header          string
+	Prefix          string
+	Separator       string
+	TimestampHeader string
+	Components      []string
 }
 
+// AuthOptionsFromMap returns a new instance of
+// AuthOptions extracted from the passed map. ok
+// is false if the passed map is nil.
 func AuthOptionsFromMap(m map[string]any) (opt AuthOptions, ok bool) {
 	if m == nil {
 		return opt, false
 	}
 
+	opt.Grant = GrantClientCredentials
+	opt.In = "header"
+	opt.Separator = "\n"
+
 	if v, ok := m["type"].(string); ok {
 		opt.Type = v
 	}
@@ -91,9 +184,102 @@ func AuthOptionsFromMap(m map[string]any) (opt AuthOptions, ok bool) {
 		opt.Token = v
 	}
 
+	if v, ok := m["tokenurl"].(string); ok {
+		opt.TokenURL = v
+	}
+
+	if v, ok := m["grant"].(string); ok {
+		opt.Grant = v
+	}
+
+	if v, ok := m["clientid"].(string); ok {
+		opt.ClientID = v
+	}
+
+	if v, ok := m["clientsecret"].(string); ok {
+		opt.ClientSecret = v
+	}
+
+	switch v := m["scope"].(type) {
+	case string:
+		opt.Scope = v
+	case []any:
+		scopes := make([]string, 0, len(v))
+		for _, s := range v {
+			scopes = append(scopes, fmt.Sprint(s))
+		}
+		opt.Scope = strings.Join(scopes, " ")
+	}
+
+	if v, ok := m["in"].(string); ok {
+		opt.In = strings.ToLower(v)
+	}
+
+	if v, ok := m["name"].(string); ok {
+		opt.Name = v
+	}
+
+	if v, ok := m["accesskey"].(string); ok {
+		opt.AccessKey = v
+	}
+
+	if v, ok := m["secretkey"].(string); ok {
+		opt.SecretKey = v
+	}
+
+	if v, ok := m["sessiontoken"].(string); ok {
+		opt.SessionToken = v
+	}
+
+	if v, ok := m["region"].(string); ok {
+		opt.Region = v
+	}
+
+	if v, ok := m["service"].(string); ok {
+		opt.Service = v
+	}
+
+	if v, ok := m["algorithm"].(string); ok {
+		opt.Algorithm = v
+	}
+
+	if v, ok := m["encoding"].(string); ok {
+		opt.Encoding = v
+	}
+
+	if v, ok := m["header"].(string); ok {
+		opt.Header = v
+	}
+
+	if v, ok := m["prefix"].(string); ok {
+		opt.Prefix = v
+	}
+
+	if v, ok := m["separator"].(string); ok {
+		opt.Separator = v
+	}
+
+	if v, ok := m["timestampheader"].(string); ok {
+		opt.TimestampHeader = v
+	}
+
+	if v, ok := m["components"].([]any); ok {
+		opt.Components = make([]string, 0, len(v))
+		for _, c := range v {
+			opt.Components = append(opt.Components, fmt.Sprint(c))
+		}
+	}
+
 	return opt, true
 }
 
+// AuthType returns the lowercase auth type.
+func (t AuthOptions) AuthType() AuthType {
+	return AuthType(strings.ToLower(t.Type))
+}
+
+// HeaderValue returns the value of the Authorization header
+// for basic and static token authentication.
 func (t AuthOptions) HeaderValue() string {
 	if t.UserName != "" && t.Password != "" {
 		v := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", t.UserName, t.Password)))
@@ -0,0 +1,43 @@
+package executor
+
+import (
+	"net/http"
+
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+// Redirect contains the data of a redirect
+// response which has been followed.
+type Redirect struct {
+	StatusCode int
+	Status     string
+	URL        string
+	Location   string
+	Header     map[string][]string
+	Cookies    []Cookie
+}
+
+// redirectsOf returns the redirects which have been
+// followed to receive the given response.
+func redirectsOf(resp *http.Response) []Redirect {
+	hops := requester.Redirects(resp)
+	if len(hops) == 0 {
+		return nil
+	}
+
+	redirects := make([]Redirect, 0, len(hops))
+	for _, hop := range hops {
+		var r Redirect
+		r.StatusCode = hop.StatusCode
+		r.Status = hop.Status
+		r.Header = hop.Header
+		r.Location = hop.Header.Get("Location")
+		r.Cookies = cookiesFromHeader(hop.Header)
+		if hop.Request != nil {
+			r.URL = hop.Request.URL.String()
+		}
+		redirects = append(redirects, r)
+	}
+
+	return redirects
+}
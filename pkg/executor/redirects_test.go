@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/callback?code=123", http.StatusFound)
+	})
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true})
+		http.Redirect(w, r, "/home", http.StatusSeeOther)
+	})
+	mux.HandleFunc("/home", func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie("session")
+		require.Nil(t, err)
+		io.WriteString(w, c.Value)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	req := requester.NewHttpWithCookies(func(*http.Client) {})
+
+	t.Run("follow", func(t *testing.T) {
+		httpReq, _ := http.NewRequest(http.MethodGet, srv.URL+"/login", nil)
+		httpResp, err := req.Do(httpReq, requester.OptionsFromMap(nil))
+		require.Nil(t, err)
+
+		resp, err := FromHttpResponse(httpResp, nil)
+		require.Nil(t, err)
+
+		assert.Equal(t, srv.URL+"/home", resp.URL)
+		assert.Equal(t, "abc", resp.Body)
+		require.Len(t, resp.Redirects, 2)
+
+		assert.Equal(t, http.StatusFound, resp.Redirects[0].StatusCode)
+		assert.Equal(t, srv.URL+"/login", resp.Redirects[0].URL)
+		assert.Equal(t, "/callback?code=123", resp.Redirects[0].Location)
+		assert.Empty(t, resp.Redirects[0].Cookies)
+
+		assert.Equal(t, http.StatusSeeOther, resp.Redirects[1].StatusCode)
+		assert.Equal(t, srv.URL+"/callback?code=123", resp.Redirects[1].URL)
+		assert.Equal(t, []Cookie{{Name: "session", Value: "abc", Path: "/", HttpOnly: true}},
+			resp.Redirects[1].Cookies)
+	})
+
+	t.Run("no-follow", func(t *testing.T) {
+		httpReq, _ := http.NewRequest(http.MethodGet, srv.URL+"/login", nil)
+		httpResp, err := req.Do(httpReq, requester.OptionsFromMap(map[string]any{"followredirects": false}))
+		require.Nil(t, err)
+
+		resp, err := FromHttpResponse(httpResp, nil)
+		require.Nil(t, err)
+
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+		assert.Equal(t, srv.URL+"/login", resp.URL)
+		assert.Empty(t, resp.Redirects)
+	})
+}
@@ -0,0 +1,236 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/studio-b12/goat/pkg/clr"
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/studio-b12/goat/pkg/util"
+)
+
+const reportIndent = "    "
+
+// ReportOptions configures the output of
+// WriteFailureReport.
+type ReportOptions struct {
+	// BodyLimit is the maximum number of bytes shown
+	// of request and response bodies. Bodies are not
+	// truncated if BodyLimit is 0.
+	BodyLimit int
+
+	// ContextLines is the number of script lines shown
+	// before and after the line which caused an error.
+	ContextLines int
+}
+
+// WriteFailureReport writes a human-readable report for each
+// of the given request errors to w. It contains the substituted
+// request, the received response and the script line which
+// caused the error, if available.
+func WriteFailureReport(w io.Writer, reqErrs []*RequestError, opts ReportOptions) error {
+	var sb strings.Builder
+
+	sb.WriteString(clr.Print(clr.Format(
+		fmt.Sprintf("FAILURES (%d)", len(reqErrs)), clr.ColorFGRed, clr.FormatBold)))
+	sb.WriteString("\n")
+
+	for i, reqErr := range reqErrs {
+		req := reqErr.Request
+
+		sb.WriteString("\n")
+		sb.WriteString(clr.Print(
+			clr.Format(fmt.Sprintf("[%d/%d] %s %s", i+1, len(reqErrs), req.Method, req.URI), clr.ColorFGRed, clr.FormatBold),
+			clr.Format(fmt.Sprintf(" (%s:%d)", req.Path, req.PosLine), clr.ColorFGBlack)))
+		sb.WriteString("\n\n")
+
+		writeReportHeading(&sb, "Request")
+		writeReportRequest(&sb, &req, opts)
+
+		if reqErr.Response != nil {
+			writeReportHeading(&sb, "Response")
+			writeReportResponse(&sb, reqErr.Response, opts)
+		}
+
+		if scriptErr, ok := errs.As[*ScriptError](reqErr); ok && scriptErr.Line > 0 {
+			if pos, ok := scriptErr.SourcePos(); ok {
+				writeReportHeading(&sb, fmt.Sprintf("[%s] %s", scriptErr.Block, pos))
+			} else {
+				writeReportHeading(&sb, fmt.Sprintf("[%s] line %d", scriptErr.Block, scriptErr.Line))
+			}
+			writeReportSource(&sb, scriptErr, opts.ContextLines)
+		}
+
+		writeReportHeading(&sb, "Error")
+		writeReportIndented(&sb, clr.Print(clr.Format(reqErr.Error(), clr.ColorFGRed)))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeReportHeading(sb *strings.Builder, heading string) {
+	sb.WriteString("  ")
+	sb.WriteString(clr.Print(clr.Format(heading, clr.ColorFGPurple, clr.FormatBold)))
+	sb.WriteString("\n")
+}
+
+func writeReportRequest(sb *strings.Builder, req *goatfile.Request, opts ReportOptions) {
+	uri := req.URI
+	if len(req.QueryParams) > 0 && !req.IsGrpc() {
+		if httpReq, err := req.ToHttpRequest(); err == nil {
+			uri = httpReq.URL.String()
+		}
+	}
+
+	lines := []string{req.Method + " " + uri}
+	lines = append(lines, formatReportHeader(req.Header)...)
+	writeReportIndented(sb, strings.Join(lines, "\n"))
+
+	if body := formatReportRequestBody(req.Body, opts.BodyLimit); body != "" {
+		sb.WriteString("\n")
+		writeReportIndented(sb, body)
+	}
+	sb.WriteString("\n")
+}
+
+func writeReportResponse(sb *strings.Builder, resp *Response, opts ReportOptions) {
+	lines := []string{fmt.Sprintf("%s %s", resp.Proto, clr.Print(clr.Format(resp.Status, reportStatusColor(resp.StatusCode))))}
+	lines = append(lines, formatReportHeader(resp.Header)...)
+	writeReportIndented(sb, strings.Join(lines, "\n"))
+
+	if body := formatReportResponseBody(resp, opts.BodyLimit); body != "" {
+		sb.WriteString("\n")
+		writeReportIndented(sb, body)
+	}
+	sb.WriteString("\n")
+}
+
+func writeReportSource(sb *strings.Builder, scriptErr *ScriptError, contextLines int) {
+	lines := strings.Split(strings.TrimRight(scriptErr.Source, "\n"), "\n")
+
+	// Lines are numbered as in the source file
+	// of the script, if its position is known.
+	offset := 0
+	if !scriptErr.Pos.IsZero() {
+		offset = scriptErr.Pos.Line - 1
+	}
+
+	from := max(scriptErr.Line-contextLines, 1)
+	to := min(scriptErr.Line+contextLines, len(lines))
+	width := len(fmt.Sprint(to + offset))
+
+	var out []string
+	for n := from; n <= to; n++ {
+		number := fmt.Sprintf("%*d │ ", width, n+offset)
+		if n != scriptErr.Line {
+			out = append(out, "  "+clr.Print(clr.Format(number, clr.ColorFGBlack))+lines[n-1])
+			continue
+		}
+
+		out = append(out, clr.Print(
+			clr.Format("> "+number, clr.ColorFGRed, clr.FormatBold),
+			clr.Format(lines[n-1], clr.ColorFGRed, clr.FormatBold)))
+		if scriptErr.Column > 0 {
+			out = append(out, strings.Repeat(" ", width+2)+clr.Print(clr.Format(" │ ", clr.ColorFGBlack))+
+				strings.Repeat(" ", scriptErr.Column-1)+clr.Print(clr.Format("^", clr.ColorFGRed, clr.FormatBold)))
+		}
+	}
+
+	writeReportIndented(sb, strings.Join(out, "\n"))
+	sb.WriteString("\n")
+}
+
+func writeReportIndented(sb *strings.Builder, s string) {
+	for _, line := range strings.Split(s, "\n") {
+		sb.WriteString(reportIndent)
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+}
+
+func formatReportHeader(header map[string][]string) []string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, v := range header[key] {
+			lines = append(lines, clr.Print(clr.Format(key+":", clr.ColorFGCyan))+" "+v)
+		}
+	}
+	return lines
+}
+
+func formatReportRequestBody(body goatfile.Data, limit int) string {
+	switch b := body.(type) {
+	case nil, goatfile.NoContent:
+		return ""
+	case goatfile.StringContent:
+		return truncateReportBody(string(b), limit)
+	}
+
+	data, err := util.ReadReaderToString(body.Reader())
+	if err != nil {
+		return clr.Print(clr.Format(fmt.Sprintf("<failed reading body: %s>", err), clr.ColorFGBlack))
+	}
+	return formatReportRawBody([]byte(data), limit)
+}
+
+func formatReportResponseBody(resp *Response, limit int) string {
+	switch resp.Body.(type) {
+	case map[string]any, []any:
+		data, err := json.MarshalIndent(resp.Body, "", "  ")
+		if err == nil {
+			return truncateReportBody(string(data), limit)
+		}
+	}
+	return formatReportRawBody(resp.BodyRaw, limit)
+}
+
+func formatReportRawBody(data []byte, limit int) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if !utf8.Valid(data) {
+		return clr.Print(clr.Format(fmt.Sprintf("<%d bytes of binary data>", len(data)), clr.ColorFGBlack))
+	}
+	return truncateReportBody(string(data), limit)
+}
+
+// truncateReportBody truncates s to the given limit of
+// bytes without splitting runes. If limit is 0, s is
+// returned as is.
+func truncateReportBody(s string, limit int) string {
+	s = strings.TrimRight(s, "\n")
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + clr.Print(clr.Format(
+		fmt.Sprintf("… (%d more bytes)", len(s)-cut), clr.ColorFGBlack))
+}
+
+func reportStatusColor(code int) clr.FormatCode {
+	switch {
+	case code >= 500:
+		return clr.ColorFGRed
+	case code >= 400:
+		return clr.ColorFGBrown
+	default:
+		return clr.ColorFGGreen
+	}
+}
@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/clr"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestWriteFailureReport(t *testing.T) {
+	clr.SetEnable(false)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid name"}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.goat": `
+POST {{.instance}}/users
+
+[Header]
+Content-Type: application/json
+
+[Body]
+{"name": "{{.name}}"}
+
+[Script]
+const status = response.StatusCode;
+assert(status === 201, ` + "`status was ${status}`" + `);
+info("created");
+
+[Options]
+noabort = true
+
+---
+
+GET {{.instance}}/{{.missing}}
+
+[Options]
+noabort = true
+`,
+	})
+
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+	_, err := exec.Execute([]string{filepath.Join(dir, "test.goat")},
+		engine.State{"instance": srv.URL, "name": strings.Repeat("a", 50)}, false)
+	require.NotNil(t, err)
+
+	reqErrs := RequestErrors(err)
+	require.Len(t, reqErrs, 2)
+
+	pos, ok := errorSourcePos(reqErrs[0])
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "test.goat")+":12:7", pos.String())
+	pos, ok = errorSourcePos(reqErrs[1])
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "test.goat")+":20:21", pos.String())
+
+	require.NotNil(t, reqErrs[0].Response)
+	assert.Equal(t, http.StatusBadRequest, reqErrs[0].Response.StatusCode)
+	assert.Equal(t, srv.URL+"/users", reqErrs[0].Request.URI)
+
+	scriptErr, ok := errs.As[*ScriptError](reqErrs[0])
+	require.True(t, ok)
+	assert.Equal(t, "Script", scriptErr.Block)
+	assert.Equal(t, 2, scriptErr.Line)
+	assert.Equal(t, 7, scriptErr.Column)
+
+	assert.Nil(t, reqErrs[1].Response)
+	assert.Equal(t, "{{.instance}}/{{.missing}}", reqErrs[1].Request.URI)
+
+	var out strings.Builder
+	err = WriteFailureReport(&out, reqErrs, ReportOptions{BodyLimit: 20, ContextLines: 1})
+	require.Nil(t, err)
+
+	report := out.String()
+	assert.Contains(t, report, "FAILURES (2)")
+	assert.Contains(t, report, "[1/2] POST "+srv.URL+"/users")
+	assert.Contains(t, report, "    Content-Type: application/json\n")
+	assert.Contains(t, report, `    {"name": "aaaaaaaaaa… (42 more bytes)`)
+	assert.Contains(t, report, "    HTTP/1.1 400 Bad Request\n")
+	assert.Contains(t, report, "    {\n      \"error\": \"invali… (9 more bytes)")
+	assert.Contains(t, report,
+		"  [Script] "+filepath.Join(dir, "test.goat")+":12:7\n"+
+			"      11 │ const status = response.StatusCode;\n"+
+			"    > 12 │ assert(status === 201, `status was ${status}`);\n"+
+			"         │       ^\n"+
+			"      13 │ info(\"created\");\n")
+	assert.Contains(t, report, "    script failed: assertion failed: status was 400\n")
+	assert.Contains(t, report, "[2/2] GET {{.instance}}/{{.missing}}")
+	assert.Contains(t, report, `map has no entry for key "missing"`)
+}
+
+func TestTruncateReportBody(t *testing.T) {
+	clr.SetEnable(false)
+
+	assert.Equal(t, "hello", truncateReportBody("hello\n", 0))
+	assert.Equal(t, "hello", truncateReportBody("hello", 5))
+	assert.Equal(t, "hel… (2 more bytes)", truncateReportBody("hello", 3))
+	assert.Equal(t, "a… (4 more bytes)", truncateReportBody("aäö", 2))
+}
@@ -25,20 +25,45 @@ func (t RawData) String() string {
 // Response is the model passed into the engine
 // state containing the requests response data.
 type Response struct {
+	URL           string
 	StatusCode    int
 	Status        string
 	Proto         string
 	ProtoMajor    int
 	ProtoMinor    int
 	Header        map[string][]string
+	Trailer       map[string][]string
 	ContentLength int64
 	BodyRaw       RawData
 	Body          any
+	Events        []Event
+	Timings       Timings
+	TLS           *TLSInfo
+	Redirects     []Redirect
+	Cookies       []Cookie
 }
 
 // FromHttpResponse builds a Response from the
 // given Http Response reference.
+//
+// If the response is a stream (like Server-Sent Events
+// or newline delimited JSON), the events are read
+// incrementally as described in FromHttpStreamResponse.
 func FromHttpResponse(resp *http.Response, options map[string]any) (Response, error) {
+	return FromHttpStreamResponse(resp, options, nil)
+}
+
+// FromHttpStreamResponse builds a Response from the
+// given Http Response reference.
+//
+// When the response type is either set to 'sse' or
+// 'ndjson' via the 'responsetype' option or detected
+// from the Content-Type header, the body is read as
+// stream of events which are set to Response.Events.
+// Reading the stream stops when the stream has ended,
+// the 'maxevents' count or the 'streamtimeout' has been
+// reached or when the given until predicate returns true.
+func FromHttpStreamResponse(resp *http.Response, options map[string]any, until StreamPredicate) (Response, error) {
 	var r Response
 
 	r.StatusCode = resp.StatusCode
@@ -48,6 +73,24 @@ func FromHttpResponse(resp *http.Response, options map[string]any) (Response, er
 	r.ProtoMinor = resp.ProtoMinor
 	r.Header = resp.Header
 	r.ContentLength = resp.ContentLength
+	r.TLS = tlsInfoFromState(resp.TLS)
+	r.Redirects = redirectsOf(resp)
+	r.Cookies = cookiesFromHeader(resp.Header)
+	if resp.Request != nil {
+		r.URL = resp.Request.URL.String()
+	}
+
+	if streamType, ok := streamResponseType(responseTypeOf(r.Header, options)); ok {
+		events, data, err := readEvents(resp.Body, streamType, StreamOptionsFromMap(options), until)
+		if err != nil {
+			return Response{}, errs.WithPrefix("failed reading response stream:", err)
+		}
+		r.Events = events
+		if len(data) > 0 {
+			r.BodyRaw = data
+		}
+		return r, nil
+	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -55,18 +98,16 @@ func FromHttpResponse(resp *http.Response, options map[string]any) (Response, er
 			errs.WithPrefix("failed reading response body:", err)
 	}
 
+	// Trailers are only available after the
+	// body has been read completely.
+	r.Trailer = resp.Trailer
+
 	// Try to parse body depending on 'repsponsetype' option.
 	// If 'responsetype' is not set, try to use response
 	// Content-Type header instead.
 	if len(data) > 0 {
 		r.BodyRaw = data
-		responseType, ok := options["responsetype"].(string)
-		if !ok {
-			contentTypeHeader, ok := r.Header["Content-Type"]
-			if ok {
-				responseType = contentTypeHeader[0]
-			}
-		}
+		responseType := responseTypeOf(r.Header, options)
 
 		// responseType 'raw' prevents body parsing
 		// if required and assigns raw bytes to 'Body'
@@ -85,6 +126,19 @@ func FromHttpResponse(resp *http.Response, options map[string]any) (Response, er
 	return r, nil
 }
 
+// responseTypeOf returns the value of the 'responsetype'
+// option, if set. Otherwise, the value of the Content-Type
+// header is returned.
+func responseTypeOf(header http.Header, options map[string]any) string {
+	if responseType, ok := options["responsetype"].(string); ok {
+		return responseType
+	}
+	if contentTypeHeader, ok := header["Content-Type"]; ok {
+		return contentTypeHeader[0]
+	}
+	return ""
+}
+
 func (t Response) String() string {
 	var sb strings.Builder
 
@@ -95,7 +149,14 @@ func (t Response) String() string {
 		}
 	}
 
-	if t.Body != nil && json.Valid(t.BodyRaw) {
+	if len(t.Events) != 0 {
+		for _, ev := range t.Events {
+			if ev.Type != "" {
+				fmt.Fprintf(&sb, "[%s] ", ev.Type)
+			}
+			fmt.Fprintf(&sb, "%s\n", ev.Data)
+		}
+	} else if t.Body != nil && json.Valid(t.BodyRaw) {
 		enc := json.NewEncoder(&sb)
 		enc.SetIndent("", "  ")
 		// This shouldn't error because it was decoded by
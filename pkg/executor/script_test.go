@@ -0,0 +1,233 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/stdlib"
+)
+
+func TestExecute_Transpile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"user": {"name": "goat", "tags": ["a", "b"]}}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"enabled.goat": `
+### Defaults
+
+[Options]
+transpile = true
+
+### Tests
+
+GET {{.instance}}
+
+[Script]
+type User = { name: string; tags: string[] };
+const { name, tags: [first, ...rest] }: User = response.Body.user;
+const upper = (s?: string): string => s?.toUpperCase() ?? "";
+var result = ` + "`${upper(name)}-${first}-${rest.length}`" + `;
+var missing = response.Body.user?.address?.street ?? "none";
+`,
+		"disabled.goat": `
+### Defaults
+
+[Options]
+transpile = false
+
+### Tests
+
+GET {{.instance}}
+
+[Script]
+var result: string = "goat";
+`,
+	})
+
+	params := engine.State{"instance": srv.URL}
+
+	t.Run("enabled", func(t *testing.T) {
+		eng, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "enabled.goat"), params)
+		require.Nil(t, err)
+
+		state := eng.State()
+		assert.Equal(t, "GOAT-a-1", state["result"])
+		assert.Equal(t, "none", state["missing"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := executeGoatfile(t, func() engine.Engine {
+			return engine.NewGojaWithOptions(engine.GojaOptions{Transpile: true})
+		}, filepath.Join(dir, "disabled.goat"), params)
+		require.NotNil(t, err)
+
+		_, _, ok := engine.ErrorPosition(err)
+		assert.True(t, ok, err)
+	})
+}
+
+func TestExecute_StdlibBuiltins(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.goat": `
+GET {{.instance}}
+
+[Header]
+Authorization: Bearer {{ jwtSign .claims "secret" }}
+
+[Script]
+const token = response.BodyRaw.toString().replace("Bearer ", "");
+assert(jwtVerify(token, "secret"), "invalid token");
+var sub = jwtDecode(token).payload.sub;
+var encoded = base64UrlUnpadded(hex(sub));
+var decoded = hexDecode(base64Decode(encoded));
+var mac = hmac("sha256", "key", sub);
+var id = uuid();
+var city = fakeAddress("fr").City;
+var role = fakePick(["admin", "user"]);
+var email = "{{ fakeEmail "de" }}";
+`,
+	})
+
+	eng, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "test.goat"),
+		engine.State{"instance": srv.URL, "claims": map[string]any{"sub": "goat"}})
+	require.Nil(t, err)
+
+	state := eng.State()
+	assert.Equal(t, "goat", state["sub"])
+	assert.Equal(t, "goat", state["decoded"])
+	assert.Equal(t, "Njc2ZjYxNzQ", state["encoded"])
+	assert.Len(t, state["mac"], 64)
+	assert.Len(t, state["id"], 36)
+	assert.NotEmpty(t, state["city"])
+	assert.Contains(t, []any{"admin", "user"}, state["role"])
+	assert.Contains(t, state["email"], "@example.")
+}
+
+func TestExecute_FileBuiltins(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.Copy(w, r.Body)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	artifacts := filepath.Join(dir, "artifacts")
+	writeFiles(t, dir, map[string]string{
+		"fixtures/user.json": `{"name": "goat"}`,
+		"tests/test.goat": `
+GET {{.instance}}
+
+[PreScript]
+var user = readJSON("../fixtures/user.json");
+var raw = readFile("../fixtures/user.json");
+var home = env("GOAT_TEST_HOME");
+var unset = env("GOAT_TEST_UNSET");
+
+[Body]
+{{ json .user }}
+
+[Script]
+var written = writeFile("responses/user.json", response.Body);
+writeFile("raw.txt", response.BodyRaw.toString());
+
+---
+
+GET {{.instance}}
+
+[Script]
+readFile("../../outside.txt");
+`,
+	})
+	t.Setenv("GOAT_TEST_HOME", "/home/goat")
+
+	eng, err := executeGoatfile(t, func() engine.Engine {
+		return engine.NewGojaWithOptions(engine.GojaOptions{
+			Files: engine.FileAccess{Root: dir, ArtifactsDir: artifacts},
+		})
+	}, filepath.Join(dir, "tests", "test.goat"), engine.State{"instance": srv.URL})
+	assert.ErrorIs(t, err, engine.ErrFileAccessDenied)
+
+	state := eng.State()
+	assert.Equal(t, map[string]any{"name": "goat"}, state["user"])
+	assert.Equal(t, `{"name": "goat"}`, state["raw"])
+	assert.Equal(t, "/home/goat", state["home"])
+	assert.NotContains(t, state, "unset")
+	assert.Equal(t, filepath.Join(artifacts, "responses", "user.json"), state["written"])
+
+	data, err := os.ReadFile(filepath.Join(artifacts, "responses", "user.json"))
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"name": "goat"}`, string(data))
+
+	data, err = os.ReadFile(filepath.Join(artifacts, "raw.txt"))
+	require.Nil(t, err)
+	assert.Equal(t, `{"name":"goat"}`+"\n", string(data))
+}
+
+func TestExecute_Seed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(r.Header.Get("X-Id")))
+	}))
+	defer srv.Close()
+	t.Cleanup(func() { stdlib.SetNow(time.Time{}) })
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.goat": `
+GET {{.instance}}
+
+[Header]
+X-Id: {{ randomString 12 }}
+
+[Script]
+var id = response.BodyRaw.toString();
+var rand = Math.random();
+var now = new Date().toISOString();
+var ts = "{{ timestamp "RFC3339" }}";
+var name = fakeName();
+`,
+	})
+
+	run := func() engine.State {
+		eng, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "test.goat"),
+			engine.State{"instance": srv.URL})
+		require.Nil(t, err)
+		return eng.State()
+	}
+
+	stdlib.SetNow(time.Date(2024, 2, 29, 12, 30, 0, 0, time.UTC))
+
+	stdlib.Seed(42)
+	first := run()
+	stdlib.Seed(42)
+	second := run()
+
+	assert.Len(t, first["id"], 12)
+	for _, name := range []string{"id", "rand", "now", "ts", "name"} {
+		assert.Equal(t, first[name], second[name], name)
+	}
+	assert.Equal(t, "2024-02-29T12:30:00.000Z", first["now"])
+	assert.Equal(t, "2024-02-29T12:30:00Z", first["ts"])
+
+	stdlib.Seed(43)
+	assert.NotEqual(t, first["id"], run()["id"])
+}
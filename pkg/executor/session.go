@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/zekrotja/rogu/log"
+)
+
+// Session executes Goatfiles and expressions one after
+// another in the same engine, so that the state is kept
+// between executions.
+type Session struct {
+	executor *Executor
+	eng      engine.Engine
+}
+
+// NewSession returns a new Session using the given
+// initialParams as initial state.
+func (t *Executor) NewSession(initialParams engine.State) *Session {
+	eng := t.newEngine()
+	eng.SetState(initialParams)
+
+	return &Session{
+		executor: t,
+		eng:      eng,
+	}
+}
+
+// Execute runs the given parsed Goatfile in the state
+// of the session.
+func (t *Session) Execute(gf goatfile.Goatfile) (Result, error) {
+	return t.executor.executeGoatfile(log.Tagged(gf.Path), gf, t.eng, false, true)
+}
+
+// Eval evaluates the given expression in the state
+// of the session and returns its result.
+func (t *Session) Eval(expr string) (any, error) {
+	return t.eng.Eval(expr)
+}
+
+// State returns the current state of the session.
+func (t *Session) State() engine.State {
+	return t.eng.State()
+}
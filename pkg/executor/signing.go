@@ -0,0 +1,330 @@
+package executor
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+var (
+	ErrMissingSigningKey    = errors.New("no signing key has been specified")
+	ErrMissingRegionService = errors.New("region and service must be specified for sigv4 signing")
+	ErrUnsupportedAlgorithm = errors.New("unsupported hmac algorithm")
+	ErrUnsupportedEncoding  = errors.New("unsupported signature encoding, must be 'hex' or 'base64'")
+	ErrInvalidHmacComponent = errors.New("invalid hmac canonicalization component")
+	ErrNoHmacComponents     = errors.New("no hmac canonicalization components have been specified")
+)
+
+const (
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	sigV4DateTime  = "20060102T150405Z"
+	sigV4Date      = "20060102"
+)
+
+// defaultHmacComponents are used to build the string to sign
+// for hmac signatures when no components are specified.
+var defaultHmacComponents = []string{"method", "path", "query", "bodyhash"}
+
+// readBody reads the body of the given request and replaces
+// it with a new reader over the read data so that the request
+// can still be sent afterwards.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, errs.WithPrefix("failed reading request body:", err)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+
+	return data, nil
+}
+
+func hmacSum(newHash func() hash.Hash, key []byte, data string) []byte {
+	mac := hmac.New(newHash, key)
+	io.WriteString(mac, data)
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// signSigV4 signs the given request using the AWS Signature
+// Version 4 signing process with the credentials, region and
+// service specified in opt at the given time.
+func signSigV4(req *http.Request, opt AuthOptions, now time.Time) error {
+	if opt.AccessKey == "" || opt.SecretKey == "" {
+		return ErrMissingSigningKey
+	}
+	if opt.Region == "" || opt.Service == "" {
+		return ErrMissingRegionService
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	now = now.UTC()
+	payloadHash := sha256Hex(body)
+	isS3 := strings.EqualFold(opt.Service, "s3")
+
+	req.Header.Set("X-Amz-Date", now.Format(sigV4DateTime))
+	if isS3 {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+	if opt.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", opt.SessionToken)
+	}
+	req.Header.Del("Authorization")
+
+	signedHeaders, canonicalHeaders := sigV4CanonicalHeaders(req)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4CanonicalPath(req.URL, !isS3),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(sigV4Date), opt.Region, opt.Service, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.Format(sigV4DateTime),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSum(sha256.New, []byte("AWS4"+opt.SecretKey), now.Format(sigV4Date))
+	key = hmacSum(sha256.New, key, opt.Region)
+	key = hmacSum(sha256.New, key, opt.Service)
+	key = hmacSum(sha256.New, key, "aws4_request")
+	signature := hex.EncodeToString(hmacSum(sha256.New, key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, opt.AccessKey, scope, signedHeaders, signature))
+
+	return nil
+}
+
+// sigV4CanonicalHeaders returns the list of signed header names
+// and the canonical headers string. All headers set to the request
+// as well as the host are signed.
+func sigV4CanonicalHeaders(req *http.Request) (signed, canonical string) {
+	headers := map[string]string{"host": requestHost(req)}
+	for name, values := range req.Header {
+		trimmed := make([]string, 0, len(values))
+		for _, v := range values {
+			trimmed = append(trimmed, strings.Join(strings.Fields(v), " "))
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(headers[name])
+		sb.WriteByte('\n')
+	}
+
+	return strings.Join(names, ";"), sb.String()
+}
+
+// sigV4CanonicalPath returns the URI encoded path of u. For all
+// services except S3, each path segment is encoded twice.
+func sigV4CanonicalPath(u *url.URL, doubleEncode bool) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segment = unescaped
+		}
+		segment = uriEncode(segment)
+		if doubleEncode {
+			segment = uriEncode(segment)
+		}
+		segments[i] = segment
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery returns the URI encoded query parameters
+// sorted by key and value.
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(key)+"="+uriEncode(v))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes all characters except the unreserved
+// characters as specified in RFC 3986.
+func uriEncode(s string) string {
+	var sb strings.Builder
+	for _, b := range []byte(s) {
+		if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			sb.WriteByte(b)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+	return sb.String()
+}
+
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+// signHmac signs the given request by building a string to sign
+// from the canonicalization components specified in opt. The
+// resulting signature is set to the configured header.
+func signHmac(req *http.Request, opt AuthOptions, now time.Time) error {
+	if opt.SecretKey == "" {
+		return ErrMissingSigningKey
+	}
+
+	var newHash func() hash.Hash
+	switch strings.ToLower(opt.Algorithm) {
+	case "", "sha256":
+		newHash = sha256.New
+	case "sha512":
+		newHash = sha512.New
+	case "sha1":
+		newHash = sha1.New
+	case "md5":
+		newHash = md5.New
+	default:
+		return errs.WithSuffix(ErrUnsupportedAlgorithm, fmt.Sprintf("(%s)", opt.Algorithm))
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	if opt.TimestampHeader != "" {
+		req.Header.Set(opt.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	}
+
+	components := opt.Components
+	if components == nil {
+		components = defaultHmacComponents
+	}
+	if len(components) == 0 {
+		return ErrNoHmacComponents
+	}
+
+	parts := make([]string, 0, len(components))
+	for _, component := range components {
+		part, err := hmacComponent(req, body, component)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, part)
+	}
+
+	mac := hmacSum(newHash, []byte(opt.SecretKey), strings.Join(parts, opt.Separator))
+
+	var signature string
+	switch strings.ToLower(opt.Encoding) {
+	case "", "hex":
+		signature = hex.EncodeToString(mac)
+	case "base64":
+		signature = base64.StdEncoding.EncodeToString(mac)
+	default:
+		return ErrUnsupportedEncoding
+	}
+
+	header := opt.Header
+	if header == "" {
+		header = "Authorization"
+	}
+	req.Header.Set(header, opt.Prefix+signature)
+
+	return nil
+}
+
+// hmacComponent returns the value of the given canonicalization
+// component for the given request and body.
+func hmacComponent(req *http.Request, body []byte, component string) (string, error) {
+	name, arg, _ := strings.Cut(component, ":")
+
+	switch strings.ToLower(name) {
+	case "method":
+		return req.Method, nil
+	case "host":
+		return requestHost(req), nil
+	case "path":
+		if path := req.URL.EscapedPath(); path != "" {
+			return path, nil
+		}
+		return "/", nil
+	case "query":
+		return canonicalQuery(req.URL.Query()), nil
+	case "url":
+		return req.URL.String(), nil
+	case "body":
+		return string(body), nil
+	case "bodyhash":
+		return sha256Hex(body), nil
+	case "header":
+		if arg == "" {
+			return "", errs.WithSuffix(ErrInvalidHmacComponent, fmt.Sprintf("(%s)", component))
+		}
+		return req.Header.Get(arg), nil
+	case "literal":
+		return arg, nil
+	default:
+		return "", errs.WithSuffix(ErrInvalidHmacComponent, fmt.Sprintf("(%s)", component))
+	}
+}
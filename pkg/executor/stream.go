@@ -0,0 +1,203 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+const (
+	responseTypeSSE    = "sse"
+	responseTypeNDJSON = "ndjson"
+)
+
+// Event holds a single event read from a
+// streamed response body.
+type Event struct {
+	Type  string
+	ID    string
+	Retry int
+	Data  string
+	Body  any
+}
+
+// StreamPredicate is called for each event read from
+// a streamed response. When it returns true, reading
+// further events from the stream is stopped.
+type StreamPredicate func(ev Event) (bool, error)
+
+// streamResponseType returns the streaming response type
+// which shall be used for the given responsetype option
+// or Content-Type header value. If the response is not
+// a stream, ok is returned as false.
+func streamResponseType(responseType string) (typ string, ok bool) {
+	switch {
+	case responseType == responseTypeSSE,
+		strings.Contains(responseType, "text/event-stream"):
+		return responseTypeSSE, true
+	case responseType == responseTypeNDJSON,
+		strings.Contains(responseType, "application/x-ndjson"),
+		strings.Contains(responseType, "application/jsonl"):
+		return responseTypeNDJSON, true
+	}
+
+	return "", false
+}
+
+// readEvents reads events incrementally from the given body
+// until either the stream ends, the maximum number of events
+// specified in opts has been reached, the optional predicate
+// until returns true or the timeout specified in opts has
+// passed.
+//
+// The read events are returned as well as the raw data
+// consumed from the body.
+func readEvents(body io.ReadCloser, typ string, opts StreamOptions, until StreamPredicate) ([]Event, []byte, error) {
+	defer body.Close()
+
+	var timedOut atomic.Bool
+	if opts.Timeout > 0 {
+		timer := time.AfterFunc(opts.Timeout, func() {
+			timedOut.Store(true)
+			body.Close()
+		})
+		defer timer.Stop()
+	}
+
+	var raw bytes.Buffer
+	scanner := bufio.NewScanner(io.TeeReader(body, &raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var next func() (Event, bool, error)
+	switch typ {
+	case responseTypeSSE:
+		next = sseReader(scanner)
+	case responseTypeNDJSON:
+		next = ndjsonReader(scanner)
+	default:
+		return nil, nil, errors.New("invalid stream response type: " + typ)
+	}
+
+	var events []Event
+	for opts.MaxEvents <= 0 || len(events) < opts.MaxEvents {
+		ev, ok, err := next()
+		if err != nil {
+			if timedOut.Load() {
+				break
+			}
+			return nil, nil, err
+		}
+		if !ok {
+			break
+		}
+
+		events = append(events, ev)
+
+		if until != nil {
+			stop, err := until(ev)
+			if err != nil {
+				return nil, nil, errs.WithPrefix("stream predicate failed:", err)
+			}
+			if stop {
+				break
+			}
+		}
+	}
+
+	return events, raw.Bytes(), nil
+}
+
+// sseReader returns a function reading the next event
+// from the given scanner according to the Server-Sent
+// Events specification.
+//
+// https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+func sseReader(scanner *bufio.Scanner) func() (Event, bool, error) {
+	return func() (Event, bool, error) {
+		var (
+			ev      Event
+			data    []string
+			hasData bool
+		)
+
+		for scanner.Scan() {
+			line := strings.TrimSuffix(scanner.Text(), "\r")
+
+			if line == "" {
+				if !hasData {
+					continue
+				}
+				if ev.Type == "" {
+					ev.Type = "message"
+				}
+				ev.Data = strings.Join(data, "\n")
+				ev.Body = parseEventData(ev.Data)
+				return ev, true, nil
+			}
+
+			if strings.HasPrefix(line, ":") {
+				continue
+			}
+
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+
+			switch field {
+			case "event":
+				ev.Type = value
+			case "data":
+				data = append(data, value)
+				hasData = true
+			case "id":
+				ev.ID = value
+			case "retry":
+				if v, err := strconv.Atoi(value); err == nil {
+					ev.Retry = v
+				}
+			}
+		}
+
+		return Event{}, false, scanner.Err()
+	}
+}
+
+// ndjsonReader returns a function reading the next
+// newline delimited JSON object from the given scanner.
+func ndjsonReader(scanner *bufio.Scanner) func() (Event, bool, error) {
+	return func() (Event, bool, error) {
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+
+			var body any
+			err := json.Unmarshal([]byte(line), &body)
+			if err != nil {
+				return Event{}, false, errs.WithPrefix("failed unmarshalling json line:", err)
+			}
+
+			return Event{Data: line, Body: body}, true, nil
+		}
+
+		return Event{}, false, scanner.Err()
+	}
+}
+
+// parseEventData tries to parse the given data as
+// JSON. If this fails, the data is returned as string.
+func parseEventData(data string) any {
+	var v any
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return data
+	}
+	return v
+}
@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/studio-b12/goat/pkg/engine"
+)
+
+func TestReadEvents(t *testing.T) {
+	t.Run("sse", func(t *testing.T) {
+		const raw = ": comment\n" +
+			"data: hello\n\n" +
+			"event: update\n" +
+			"id: 2\n" +
+			"retry: 1000\n" +
+			"data: {\"foo\":\n" +
+			"data: \"bar\"}\n\n" +
+			"data: unterminated"
+
+		events, data, err := readEvents(io.NopCloser(strings.NewReader(raw)),
+			responseTypeSSE, StreamOptions{}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, raw, string(data))
+		assert.Equal(t, []Event{
+			{Type: "message", Data: "hello", Body: "hello"},
+			{Type: "update", ID: "2", Retry: 1000, Data: "{\"foo\":\n\"bar\"}",
+				Body: map[string]any{"foo": "bar"}},
+		}, events)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		const raw = "{\"n\":1}\n\n{\"n\":2}\n"
+
+		events, _, err := readEvents(io.NopCloser(strings.NewReader(raw)),
+			responseTypeNDJSON, StreamOptions{}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, []Event{
+			{Data: `{"n":1}`, Body: map[string]any{"n": 1.0}},
+			{Data: `{"n":2}`, Body: map[string]any{"n": 2.0}},
+		}, events)
+	})
+
+	t.Run("ndjson-invalid", func(t *testing.T) {
+		_, _, err := readEvents(io.NopCloser(strings.NewReader("{\"n\":1}\nfoo\n")),
+			responseTypeNDJSON, StreamOptions{}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("maxevents", func(t *testing.T) {
+		r, w := io.Pipe()
+		go func() {
+			for i := 0; i < 5; i++ {
+				io.WriteString(w, "data: ping\n\n")
+			}
+		}()
+
+		events, _, err := readEvents(r, responseTypeSSE, StreamOptions{MaxEvents: 3}, nil)
+		assert.Nil(t, err)
+		assert.Len(t, events, 3)
+	})
+
+	t.Run("until", func(t *testing.T) {
+		r, w := io.Pipe()
+		go func() {
+			io.WriteString(w, "{\"done\":false}\n{\"done\":true}\n")
+		}()
+
+		events, _, err := readEvents(r, responseTypeNDJSON, StreamOptions{},
+			func(ev Event) (bool, error) {
+				return ev.Body.(map[string]any)["done"].(bool), nil
+			})
+		assert.Nil(t, err)
+		assert.Len(t, events, 2)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		r, w := io.Pipe()
+		go func() {
+			io.WriteString(w, "data: ping\n\n")
+		}()
+
+		events, _, err := readEvents(r, responseTypeSSE,
+			StreamOptions{Timeout: 50 * time.Millisecond}, nil)
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+	})
+}
+
+func TestStreamResponseType(t *testing.T) {
+	typ, ok := streamResponseType("sse")
+	assert.True(t, ok)
+	assert.Equal(t, responseTypeSSE, typ)
+
+	typ, ok = streamResponseType("text/event-stream; charset=utf-8")
+	assert.True(t, ok)
+	assert.Equal(t, responseTypeSSE, typ)
+
+	typ, ok = streamResponseType("application/x-ndjson")
+	assert.True(t, ok)
+	assert.Equal(t, responseTypeNDJSON, typ)
+
+	_, ok = streamResponseType("application/json")
+	assert.False(t, ok)
+}
+
+func TestStreamPredicate(t *testing.T) {
+	eng := engine.NewGoja()
+	until := streamPredicate(eng, "event.Type === 'done'")
+
+	stop, err := until(Event{Type: "update"})
+	assert.Nil(t, err)
+	assert.False(t, stop)
+
+	stop, err = until(Event{Type: "done"})
+	assert.Nil(t, err)
+	assert.True(t, stop)
+
+	assert.NotContains(t, eng.State(), "event")
+	v, err := eng.Eval("typeof event")
+	assert.Nil(t, err)
+	assert.Equal(t, "undefined", v)
+
+	eng.Set("event", "signup")
+	stop, err = until(Event{Type: "done"})
+	assert.Nil(t, err)
+	assert.True(t, stop)
+	assert.Equal(t, "signup", eng.State()["event"])
+
+	assert.Nil(t, streamPredicate(eng, ""))
+}
@@ -0,0 +1,173 @@
+package executor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings contains the durations of the phases
+// of a request in milliseconds.
+type Timings struct {
+	// DNS is the duration of the DNS lookup.
+	DNS float64
+	// Connect is the duration of establishing
+	// the TCP connection.
+	Connect float64
+	// TLSHandshake is the duration of the
+	// TLS handshake.
+	TLSHandshake float64
+	// TTFB is the duration from sending the request
+	// until the first byte of the response has been
+	// received.
+	TTFB float64
+	// Total is the duration from sending the request
+	// until the response body has been read completely.
+	Total float64
+}
+
+// TLSInfo contains information about the TLS
+// connection a response has been received on.
+type TLSInfo struct {
+	Version            string
+	CipherSuite        string
+	NegotiatedProtocol string
+	ServerName         string
+	PeerCertificates   []CertificateInfo
+}
+
+// CertificateInfo contains information about
+// a certificate presented by the server.
+type CertificateInfo struct {
+	Subject         string
+	Issuer          string
+	DNSNames        []string
+	NotBefore       time.Time
+	NotAfter        time.Time
+	DaysUntilExpiry float64
+}
+
+// tracer records the points in time of the phases
+// of a request using httptrace.
+//
+// When multiple connections are established during
+// a request (i.e. when following redirects), the
+// durations of the phases are accumulated.
+type tracer struct {
+	mtx sync.Mutex
+
+	start     time.Time
+	firstByte time.Time
+
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+
+	dns          time.Duration
+	connect      time.Duration
+	tlsHandshake time.Duration
+}
+
+func newTracer() *tracer {
+	return &tracer{start: time.Now()}
+}
+
+func (t *tracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mtx.Lock()
+			defer t.mtx.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mtx.Lock()
+			defer t.mtx.Unlock()
+			t.dns += time.Since(t.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			t.mtx.Lock()
+			defer t.mtx.Unlock()
+			t.connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			t.mtx.Lock()
+			defer t.mtx.Unlock()
+			t.connect += time.Since(t.connectStart)
+		},
+		TLSHandshakeStart: func() {
+			t.mtx.Lock()
+			defer t.mtx.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mtx.Lock()
+			defer t.mtx.Unlock()
+			t.tlsHandshake += time.Since(t.tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			t.mtx.Lock()
+			defer t.mtx.Unlock()
+			if t.firstByte.IsZero() {
+				t.firstByte = time.Now()
+			}
+		},
+	}
+}
+
+// Timings returns the recorded timings where end
+// marks the end of the request.
+func (t *tracer) Timings(end time.Time) Timings {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	var timings Timings
+
+	timings.DNS = milliseconds(t.dns)
+	timings.Connect = milliseconds(t.connect)
+	timings.TLSHandshake = milliseconds(t.tlsHandshake)
+	if !t.firstByte.IsZero() {
+		timings.TTFB = milliseconds(t.firstByte.Sub(t.start))
+	}
+	timings.Total = milliseconds(end.Sub(t.start))
+
+	return timings
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// tlsInfoFromState builds a TLSInfo from the given
+// connection state. If state is nil, nil is returned.
+func tlsInfoFromState(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := TLSInfo{
+		Version:            tls.VersionName(state.Version),
+		CipherSuite:        tls.CipherSuiteName(state.CipherSuite),
+		NegotiatedProtocol: state.NegotiatedProtocol,
+		ServerName:         state.ServerName,
+		PeerCertificates:   make([]CertificateInfo, 0, len(state.PeerCertificates)),
+	}
+
+	for _, cert := range state.PeerCertificates {
+		info.PeerCertificates = append(info.PeerCertificates, certificateInfo(cert))
+	}
+
+	return &info
+}
+
+func certificateInfo(cert *x509.Certificate) CertificateInfo {
+	return CertificateInfo{
+		Subject:         cert.Subject.String(),
+		Issuer:          cert.Issuer.String(),
+		DNSNames:        cert.DNSNames,
+		NotBefore:       cert.NotBefore,
+		NotAfter:        cert.NotAfter,
+		DaysUntilExpiry: time.Until(cert.NotAfter).Hours() / 24,
+	}
+}
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+)
+
+func TestTracer(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		io.WriteString(w, "hello")
+	}))
+	defer srv.Close()
+
+	trace := newTracer()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+	res, err := srv.Client().Do(req)
+	require.Nil(t, err)
+
+	resp, err := FromHttpResponse(res, nil)
+	require.Nil(t, err)
+	timings := trace.Timings(time.Now())
+
+	assert.Greater(t, timings.Connect, 0.0)
+	assert.Greater(t, timings.TLSHandshake, 0.0)
+	assert.GreaterOrEqual(t, timings.TTFB, 20.0)
+	assert.GreaterOrEqual(t, timings.Total, timings.TTFB)
+
+	require.NotNil(t, resp.TLS)
+	assert.Equal(t, "TLS 1.3", resp.TLS.Version)
+	assert.NotEmpty(t, resp.TLS.CipherSuite)
+	require.Len(t, resp.TLS.PeerCertificates, 1)
+	assert.Equal(t, "O=Acme Co", resp.TLS.PeerCertificates[0].Subject)
+	assert.Greater(t, resp.TLS.PeerCertificates[0].DaysUntilExpiry, 0.0)
+}
+
+func TestExecute_TimingsExcludeAuth(t *testing.T) {
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"access_token":"token","token_type":"bearer"}`)
+	}))
+	defer tokenSrv.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.goat": `
+GET {{.instance}}
+
+[Auth]
+type = "oauth2"
+tokenurl = "{{.tokenurl}}"
+clientid = "client"
+clientsecret = "secret"
+
+[Script]
+assert(response.BodyRaw.toString() === "Bearer token", "unauthorized");
+var total = response.Timings.Total;
+var ttfb = response.Timings.TTFB;
+`,
+	})
+
+	eng, err := executeGoatfile(t, engine.NewGoja, filepath.Join(dir, "test.goat"),
+		engine.State{"instance": srv.URL, "tokenurl": tokenSrv.URL})
+	require.Nil(t, err)
+
+	state := eng.State()
+	assert.Less(t, state["total"], 200.0)
+	assert.Less(t, state["ttfb"], 200.0)
+}
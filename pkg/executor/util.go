@@ -3,6 +3,7 @@ package executor
 import (
 	"encoding/json"
 	"encoding/xml"
+	"path/filepath"
 	"strings"
 
 	"github.com/studio-b12/goat/pkg/clr"
@@ -47,3 +48,12 @@ func parseBody(data []byte, responseType string) (any, error) {
 		return string(data), nil
 	}
 }
+
+// resolvePath returns p joined with dir
+// if p is not an absolute path.
+func resolvePath(dir, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
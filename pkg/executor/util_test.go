@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+// writeFiles writes the given files, mapped by their path
+// relative to dir, to dir. Parent directories are created
+// if they do not exist.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+// executeGoatfile executes the Goatfile at path with the given
+// params using engines created by engineMaker. Engines selected
+// via the engine option of a Goatfile are created by the goja
+// and expr engine makers. The engine created last is returned
+// so that its state can be inspected.
+func executeGoatfile(t *testing.T, engineMaker func() engine.Engine, path string, params engine.State) (engine.Engine, error) {
+	t.Helper()
+
+	var eng engine.Engine
+	capture := func(maker func() engine.Engine) func() engine.Engine {
+		return func() engine.Engine {
+			eng = maker()
+			return eng
+		}
+	}
+
+	exec := New(context.Background(), capture(engineMaker), requester.NewHttpWithCookies(func(*http.Client) {}))
+	exec.Engines = map[string]func() engine.Engine{
+		"goja": capture(engine.NewGoja),
+		"expr": capture(engine.NewExpr),
+	}
+
+	_, err := exec.Execute([]string{path}, params, false)
+	return eng, err
+}
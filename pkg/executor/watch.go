@@ -0,0 +1,247 @@
+package executor
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/studio-b12/goat/pkg/clr"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/goatfile"
+	"github.com/studio-b12/goat/pkg/set"
+	"github.com/studio-b12/goat/pkg/watcher"
+	"github.com/zekrotja/rogu/log"
+)
+
+// WatchOptions configures the behavior of Watch.
+type WatchOptions struct {
+	// Interval is the interval in which the watched
+	// files are checked for changes.
+	Interval time.Duration
+
+	// Debounce is the duration waited for after the
+	// last change before the affected Goatfiles are
+	// executed again.
+	Debounce time.Duration
+
+	// BeforeRun is called with the list of changed
+	// files before affected Goatfiles are executed
+	// again.
+	BeforeRun func(changed []string)
+
+	// AfterRun is called with the result of every
+	// execution.
+	AfterRun func(res Result, err error)
+}
+
+// Watch executes the Goatfiles from the given pathes like Execute.
+// After that, the Goatfiles, the directories containing them and
+// all files they depend on are watched for changes. On change, only
+// the affected Goatfiles are executed again. Changes to global setup
+// and teardown Goatfiles cause all Goatfiles to be executed again.
+//
+// Watch blocks until the context of the Executor is done.
+func (t *Executor) Watch(pathes []string, initialParams engine.State, showTeardownParamErrors bool, opts WatchOptions) {
+	w := watcher.New(opts.Interval, opts.Debounce)
+
+	graph, graphErr := t.dependencyGraph(pathes)
+	w.Set(graph.files())
+
+	t.watchRun(nil, pathes, initialParams, showTeardownParamErrors, opts)
+
+	for changed := range w.Watch(t.ctx) {
+		prev, prevErr := graph, graphErr
+
+		graph, graphErr = t.dependencyGraph(pathes)
+		w.Set(graph.files())
+
+		// When the dependencies could not be determined
+		// before or now, all Goatfiles are executed again
+		// so that parsing errors are reported.
+		var affected set.Set[string]
+		if graphErr == nil && prevErr == nil {
+			var all bool
+			affected, all = graph.affected(prev, changed)
+			if all {
+				affected = nil
+			} else if len(affected) == 0 {
+				log.Debug().Field("changed", changed).Msg("No Goatfiles affected by changes")
+				continue
+			}
+		}
+
+		if opts.BeforeRun != nil {
+			opts.BeforeRun(changed)
+		}
+
+		t.watchRun(affected, pathes, initialParams, showTeardownParamErrors, opts)
+	}
+}
+
+func (t *Executor) watchRun(
+	affected set.Set[string],
+	pathes []string,
+	initialParams engine.State,
+	showTeardownParamErrors bool,
+	opts WatchOptions,
+) {
+	t.only = affected
+	defer func() { t.only = nil }()
+
+	res, err := t.Execute(pathes, initialParams, showTeardownParamErrors)
+	if opts.AfterRun != nil {
+		opts.AfterRun(res, err)
+	}
+
+	log.Info().Msg(clr.Print(clr.Format("Watching for changes ...", clr.ColorFGPurple, clr.FormatBold)))
+}
+
+// dependencyGraph maps the Goatfiles executed as
+// batches to the files they depend on.
+type dependencyGraph struct {
+	// global contains the global setup and teardown
+	// Goatfiles and the files they depend on.
+	global set.Set[string]
+	// batches maps the paths of the batch Goatfiles
+	// to the files they depend on including themselves.
+	batches map[string]set.Set[string]
+	// walked contains all directories and Goatfiles
+	// found in the given pathes.
+	walked set.Set[string]
+}
+
+// dependencyGraph walks the given pathes and collects the batch
+// Goatfiles and the files they depend on. If parsing fails, the
+// returned graph contains all walked directories and Goatfiles so
+// that these can still be watched.
+func (t *Executor) dependencyGraph(pathes []string) (graph dependencyGraph, err error) {
+	graph.global = set.Set[string]{}
+	graph.batches = map[string]set.Set[string]{}
+	graph.walked = set.Set[string]{}
+
+	for _, path := range pathes {
+		err = filepath.WalkDir(path, func(path string, d fs.DirEntry, e error) error {
+			if e != nil {
+				return e
+			}
+			if d.IsDir() && strings.HasPrefix(d.Name(), "_") {
+				return fs.SkipDir
+			}
+			if d.IsDir() || filepath.Ext(d.Name()) == "."+goatfile.FileExtension {
+				graph.walked.Add(filepath.Clean(path))
+			}
+			return nil
+		})
+		if err != nil {
+			return graph, err
+		}
+	}
+
+	if t.GlobalSetup != "" {
+		graph.global.Add(filepath.Clean(t.GlobalSetup))
+	}
+	for _, path := range pathes {
+		graph.global.Add(filepath.Join(path, globalSetupFileName))
+		graph.global.Add(filepath.Join(path, globalTeardownFileName))
+	}
+
+	setups, teardowns, err := t.findGlobalGoatfiles(pathes)
+	if err != nil {
+		return graph, err
+	}
+	for _, gf := range append(setups, teardowns...) {
+		t.collectDependencies(gf, graph.global)
+	}
+
+	goatfiles, err := t.parseGoatfiles(pathes)
+	if err != nil {
+		return graph, err
+	}
+	for _, gf := range goatfiles {
+		deps := set.Set[string]{}
+		deps.Add(filepath.Clean(gf.Path))
+		t.collectDependencies(gf, deps)
+		graph.batches[filepath.Clean(gf.Path)] = deps
+	}
+
+	return graph, nil
+}
+
+// collectDependencies adds the dependencies of the given Goatfile
+// to deps. Goatfiles referenced in execute statements are followed.
+func (t *Executor) collectDependencies(gf goatfile.Goatfile, deps set.Set[string]) {
+	imports := set.Set[string]{}
+	for _, imp := range gf.Imports {
+		imports.Add(filepath.Clean(imp))
+	}
+
+	for _, dep := range gf.Dependencies() {
+		dep = filepath.Clean(dep)
+		if !deps.Add(dep) {
+			continue
+		}
+
+		if imports.Contains(dep) || filepath.Ext(dep) != "."+goatfile.FileExtension {
+			continue
+		}
+
+		executed, err := t.parseGoatfile(dep)
+		if err != nil {
+			log.Debug().Err(err).Field("path", dep).Msg("Failed parsing dependency")
+			continue
+		}
+		t.collectDependencies(executed, deps)
+	}
+}
+
+// files returns all files and directories to be watched.
+func (t dependencyGraph) files() []string {
+	files := set.Set[string]{}
+	for f := range t.walked {
+		files.Add(f)
+	}
+	for f := range t.global {
+		files.Add(f)
+	}
+	for _, deps := range t.batches {
+		for f := range deps {
+			files.Add(f)
+		}
+	}
+
+	list := make([]string, 0, len(files))
+	for f := range files {
+		list = append(list, f)
+	}
+	return list
+}
+
+// affected returns the paths of the batch Goatfiles which are
+// affected by the given changed files. Newly added Goatfiles
+// compared to prev are affected as well. If a global setup or
+// teardown Goatfile is affected, all is true.
+func (t dependencyGraph) affected(prev dependencyGraph, changed []string) (affected set.Set[string], all bool) {
+	affected = set.Set[string]{}
+
+	for _, f := range changed {
+		if t.global.Contains(f) || prev.global.Contains(f) {
+			return nil, true
+		}
+	}
+
+	for path, deps := range t.batches {
+		if _, ok := prev.batches[path]; !ok {
+			affected.Add(path)
+			continue
+		}
+		for _, f := range changed {
+			if deps.Contains(f) {
+				affected.Add(path)
+				break
+			}
+		}
+	}
+
+	return affected, false
+}
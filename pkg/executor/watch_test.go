@@ -0,0 +1,132 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/requester"
+	"github.com/studio-b12/goat/pkg/set"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"_setup.goat":      "GET https://example.com/login\n",
+		"_util/auth.goat":  "GET https://example.com/auth\n",
+		"_util/token.goat": "GET https://example.com/token\n",
+		"a.goat":           "use _util/auth\n\nGET https://example.com/a\n",
+		"sub/b.goat":       "execute \"../_util/token\"\n\nGET https://example.com/b\n\n[Body]\n@body.json\n",
+		"sub/body.json":    "{}",
+	})
+
+	exec := New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+
+	graph, err := exec.dependencyGraph([]string{dir})
+	require.Nil(t, err)
+
+	a := filepath.Join(dir, "a.goat")
+	b := filepath.Join(dir, "sub", "b.goat")
+
+	assert.Equal(t, map[string]set.Set[string]{
+		a: {a: {}, filepath.Join(dir, "_util", "auth.goat"): {}},
+		b: {b: {}, filepath.Join(dir, "_util", "token.goat"): {}, filepath.Join(dir, "sub", "body.json"): {}},
+	}, graph.batches)
+	assert.True(t, graph.global.Contains(filepath.Join(dir, "_setup.goat")))
+	assert.True(t, graph.walked.Contains(filepath.Join(dir, "sub")))
+
+	affected, all := graph.affected(graph, []string{filepath.Join(dir, "_util", "auth.goat")})
+	assert.False(t, all)
+	assert.Equal(t, set.Set[string]{a: {}}, affected)
+
+	affected, all = graph.affected(graph, []string{filepath.Join(dir, "sub", "body.json")})
+	assert.False(t, all)
+	assert.Equal(t, set.Set[string]{b: {}}, affected)
+
+	_, all = graph.affected(graph, []string{filepath.Join(dir, "_setup.goat")})
+	assert.True(t, all)
+
+	writeFiles(t, dir, map[string]string{"c.goat": "GET https://example.com/c\n"})
+	newGraph, err := exec.dependencyGraph([]string{dir})
+	require.Nil(t, err)
+
+	affected, all = newGraph.affected(graph, []string{dir})
+	assert.False(t, all)
+	assert.Equal(t, set.Set[string]{filepath.Join(dir, "c.goat"): {}}, affected)
+}
+
+func TestWatch(t *testing.T) {
+	var (
+		mtx   sync.Mutex
+		calls []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		calls = append(calls, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.goat": "GET {{.instance}}/a\n",
+		"b.goat": "GET {{.instance}}/b\n",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exec := New(ctx, engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+
+	cRuns := make(chan error)
+	var changedFiles []string
+
+	go func() {
+		exec.Watch([]string{dir}, engine.State{"instance": srv.URL}, true, WatchOptions{
+			Interval:  5 * time.Millisecond,
+			Debounce:  20 * time.Millisecond,
+			BeforeRun: func(changed []string) { changedFiles = changed },
+			AfterRun:  func(_ Result, err error) { cRuns <- err },
+		})
+		close(cRuns)
+	}()
+
+	waitRun := func() {
+		t.Helper()
+		select {
+		case err := <-cRuns:
+			require.Nil(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no execution")
+		}
+	}
+
+	waitRun()
+	assert.ElementsMatch(t, []string{"/a", "/b"}, calls)
+
+	mtx.Lock()
+	calls = nil
+	mtx.Unlock()
+
+	// Ensure that the modification time differs
+	// on file systems with a low resolution.
+	time.Sleep(10 * time.Millisecond)
+	writeFiles(t, dir, map[string]string{"b.goat": "GET {{.instance}}/b2\n"})
+
+	waitRun()
+	assert.Equal(t, []string{"/b2"}, calls)
+	assert.Equal(t, []string{filepath.Join(dir, "b.goat")}, changedFiles)
+
+	cancel()
+	_, ok := <-cRuns
+	assert.False(t, ok)
+}
@@ -147,6 +147,7 @@ type NoContent struct{}
 type RequestHead struct {
 	Method string
 	Url    string
+	UrlPos Pos
 }
 
 type RequestBlock interface{}
@@ -169,14 +170,22 @@ type RequestAuth struct {
 
 type RequestBody struct {
 	DataContent
+	Pos Pos
 }
 
 type RequestPreScript struct {
 	DataContent
+	Pos Pos
 }
 
 type RequestScript struct {
 	DataContent
+	Pos Pos
+}
+
+type RequestFunctions struct {
+	DataContent
+	Pos Pos
 }
 
 type FormData struct {
@@ -0,0 +1,76 @@
+package goatfile
+
+import (
+	"path"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/goatfile/ast"
+)
+
+// Dependencies returns the paths of all files the Goatfile
+// directly depends on. These are the imported Goatfiles, the
+// Goatfiles referenced in execute statements as well as files
+// referenced in request bodies and scripts.
+//
+// Paths containing template expressions can not be resolved
+// before execution and are therefore not included. Goatfiles
+// referenced in execute statements are not followed.
+func (t Goatfile) Dependencies() []string {
+	var deps []string
+
+	deps = append(deps, t.Imports...)
+
+	if t.Defaults != nil {
+		deps = append(deps, t.Defaults.dependencies()...)
+	}
+
+	for _, sect := range [][]Action{t.Setup, t.Tests, t.Teardown} {
+		for _, act := range sect {
+			switch a := act.(type) {
+			case *Request:
+				deps = append(deps, a.dependencies()...)
+			case Execute:
+				if !isTemplated(a.File) {
+					deps = append(deps, Extend(path.Join(path.Dir(a.Path), a.File), FileExtension))
+				}
+			}
+		}
+	}
+
+	return deps
+}
+
+func (t *Request) dependencies() []string {
+	var deps []string
+	for _, data := range []Data{t.Body, t.PreScript, t.Script, t.Functions} {
+		deps = append(deps, dataDependencies(data)...)
+	}
+	return deps
+}
+
+func dataDependencies(data Data) []string {
+	var deps []string
+
+	switch d := data.(type) {
+	case FileContent:
+		if pth, err := joinPath(d.currDir, d.filePath); err == nil && !isTemplated(pth) {
+			deps = append(deps, pth)
+		}
+	case FormData:
+		for _, v := range d.fields {
+			fd, ok := v.(ast.FileDescriptor)
+			if !ok {
+				continue
+			}
+			if pth, err := joinPath(d.currDir, fd.Path); err == nil && !isTemplated(pth) {
+				deps = append(deps, pth)
+			}
+		}
+	}
+
+	return deps
+}
+
+func isTemplated(v string) bool {
+	return strings.Contains(v, "{{")
+}
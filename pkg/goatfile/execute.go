@@ -28,6 +28,14 @@ func ExecuteFromAst(a *ast.Execute, path string) (t Execute, err error) {
 	return t, nil
 }
 
+// Copy returns a deep copy of the Execute action, so
+// that its parameters can be substituted without
+// modifying the original.
+func (t Execute) Copy() Execute {
+	t.Params = copyMap(t.Params)
+	return t
+}
+
 func (t Execute) Type() ActionType {
 	return ActionExecute
 }
@@ -30,6 +30,7 @@ const (
 	optionNameBody        = optionName("body")
 	optionNamePreScript   = optionName("prescript")
 	optionNameScript      = optionName("script")
+	optionNameFunctions   = optionName("functions")
 	optionNameOptions     = optionName("options")
 	optionNameAuth        = optionName("auth")
 	optionNameFormData    = optionName("formdata")
@@ -38,6 +39,10 @@ const (
 // Goatfile holds all sections and
 // their requests.
 type Goatfile struct {
+	// Imports contains the paths of the imported
+	// Goatfiles. After unmarshalling, these are the
+	// resolved paths of all directly and transitively
+	// imported Goatfiles.
 	Imports []string
 
 	Defaults *Request
@@ -130,6 +135,38 @@ func (t *Goatfile) Merge(with Goatfile) {
 	t.Path = with.Path
 }
 
+// Copy returns a deep copy of the Goatfile, so that
+// its actions can be executed, which substitutes them
+// in place, without modifying the original Goatfile.
+func (t Goatfile) Copy() Goatfile {
+	cp := t
+	cp.Imports = slices.Clone(t.Imports)
+	cp.Defaults = t.Defaults.Copy()
+	cp.Setup = copyActions(t.Setup)
+	cp.Tests = copyActions(t.Tests)
+	cp.Teardown = copyActions(t.Teardown)
+	return cp
+}
+
+func copyActions(actions []Action) []Action {
+	if actions == nil {
+		return nil
+	}
+
+	cp := make([]Action, len(actions))
+	for i, act := range actions {
+		switch at := act.(type) {
+		case *Request:
+			cp[i] = at.Copy()
+		case Execute:
+			cp[i] = at.Copy()
+		default:
+			cp[i] = act
+		}
+	}
+	return cp
+}
+
 // String returns the Goatfile as JSON encoded string.
 func (t Goatfile) String() string {
 	return util.SafeJsonMarshalIndent(t)
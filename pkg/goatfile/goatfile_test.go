@@ -97,6 +97,39 @@ func TestMerge(t *testing.T) {
 
 // --- Helpers ---
 
+func TestCopy(t *testing.T) {
+	req := testRequest("GET", "{{.instance}}/{{.id}}")
+	req.Header.Set("X-Id", "{{.id}}")
+	req.Options = map[string]any{"tags": []any{"{{.id}}"}, "nested": map[string]any{"id": "{{.id}}"}}
+	req.Body = FormData{fields: map[string]any{"id": "{{.id}}"}}
+
+	gf := Goatfile{
+		Defaults: newRequest(),
+		Tests: []Action{
+			req,
+			Execute{File: "other", Params: map[string]any{"id": "{{.id}}"}},
+			LogSection("section"),
+		},
+	}
+
+	cp := gf.Copy()
+	params := map[string]any{"instance": "http://localhost", "id": 1}
+
+	cpReq := cp.Tests[0].(*Request)
+	assert.Nil(t, cpReq.SubstituteWithParams(params))
+	assert.Equal(t, "http://localhost/1", cpReq.URI)
+	assert.Nil(t, ApplyTemplateToMap(cp.Tests[1].(Execute).Params, params))
+
+	assert.Equal(t, "{{.instance}}/{{.id}}", req.URI)
+	assert.Equal(t, "{{.id}}", req.Header.Get("X-Id"))
+	assert.Equal(t, []any{"{{.id}}"}, req.Options["tags"])
+	assert.Equal(t, map[string]any{"id": "{{.id}}"}, req.Options["nested"])
+	assert.Equal(t, map[string]any{"id": "{{.id}}"}, req.Body.(FormData).fields)
+	assert.Equal(t, map[string]any{"id": "{{.id}}"}, gf.Tests[1].(Execute).Params)
+	assert.Equal(t, LogSection("section"), cp.Tests[2])
+	assert.NotSame(t, gf.Defaults, cp.Defaults)
+}
+
 func testRequest(method, uri string, opt ...int) *Request {
 	r := newRequest()
 	r.Method = method
@@ -117,7 +150,51 @@ func testRequestWithPath(method, uri string, path string, opt ...int) *Request {
 
 	if len(opt) > 0 {
 		r.PosLine = opt[0]
+		r.URIPos = SourcePos{File: path, Line: opt[0], Column: len(method) + 2}
 	}
 
 	return r
 }
+
+func TestDependencies(t *testing.T) {
+	raw := `
+execute "../util/login" (
+  username="foo"
+)
+
+### Setup
+
+GET https://example.com
+
+[Body]
+@data/body.json
+
+[Script]
+@scripts/check.js
+
+---
+
+POST https://example.com
+
+[FormData]
+name = "foo"
+file = @files/goat.png:image/png
+
+---
+
+POST https://example.com
+
+[Body]
+@data/{{.file}}
+`
+
+	gf, err := Unmarshal(raw, "tests/users/create.goat")
+	assert.Nil(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"tests/util/login.goat",
+		"tests/users/data/body.json",
+		"tests/users/scripts/check.js",
+		"tests/users/files/goat.png",
+	}, gf.Dependencies())
+}
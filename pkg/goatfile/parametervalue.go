@@ -2,6 +2,7 @@ package goatfile
 
 import (
 	"fmt"
+	"text/template"
 )
 
 // ParameterValue holds a go template value as string
@@ -12,8 +13,8 @@ type ParameterValue string
 // ApplyTemplate applies the passed params onto the teplate
 // value and parses the result using a new instance of Parser
 // as sub-parser.
-func (t ParameterValue) ApplyTemplate(params any) (any, error) {
-	b, err := ApplyTemplateBuf(fmt.Sprintf("{{%s}}", t), params)
+func (t ParameterValue) ApplyTemplate(params any, funcs ...template.FuncMap) (any, error) {
+	b, err := ApplyTemplateBuf(fmt.Sprintf("{{%s}}", t), params, funcs...)
 	if err != nil {
 		return nil, err
 	}
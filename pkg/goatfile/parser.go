@@ -373,6 +373,7 @@ func (t *Parser) parseRequest() (*ast.Request, []ast.Comment, error) {
 		return nil, nil, ErrNoRequestURI
 	}
 
+	req.Head.UrlPos = t.astPos()
 	tok, lit = t.s.scanString()
 	if tok != tokSTRING || lit == "" {
 		return nil, nil, ErrNoRequestURI
@@ -500,25 +501,32 @@ func (t *Parser) parseBlock() (ast.RequestBlock, []ast.Comment, error) {
 		return ast.RequestHeader{HeaderEntries: header}, comments, nil
 
 	case optionNameBody:
-		raw, err := t.parseRaw()
+		raw, pos, err := t.parseRaw()
 		if err != nil {
 			return nil, nil, err
 		}
-		return ast.RequestBody{DataContent: raw}, comments, nil
+		return ast.RequestBody{DataContent: raw, Pos: pos}, comments, nil
 
 	case optionNamePreScript:
-		raw, err := t.parseRaw()
+		raw, pos, err := t.parseRaw()
 		if err != nil {
 			return nil, nil, err
 		}
-		return ast.RequestPreScript{DataContent: raw}, comments, nil
+		return ast.RequestPreScript{DataContent: raw, Pos: pos}, comments, nil
 
 	case optionNameScript:
-		raw, err := t.parseRaw()
+		raw, pos, err := t.parseRaw()
 		if err != nil {
 			return nil, nil, err
 		}
-		return ast.RequestScript{DataContent: raw}, comments, nil
+		return ast.RequestScript{DataContent: raw, Pos: pos}, comments, nil
+
+	case optionNameFunctions:
+		raw, pos, err := t.parseRaw()
+		if err != nil {
+			return nil, nil, err
+		}
+		return ast.RequestFunctions{DataContent: raw, Pos: pos}, comments, nil
 
 	case optionNameOptions:
 		data, comms, err := t.parseBlockEntries(nil)
@@ -634,17 +642,25 @@ func (t *Parser) parseHeaders() (header ast.HeaderEntries, comments []ast.Commen
 	return header, comments, nil
 }
 
-func (t *Parser) parseRaw() (ast.DataContent, error) {
-	var out bytes.Buffer
+// parseRaw parses the raw content of a block. The returned
+// position is the position of the first character of the
+// content.
+func (t *Parser) parseRaw() (ast.DataContent, ast.Pos, error) {
+	var (
+		out   bytes.Buffer
+		start = t.astPos()
+	)
 
 	inEscape := false
 
 	r := t.s.read()
 	if r == '@' {
-		return t.parseFileDescriptor()
+		fd, err := t.parseFileDescriptor()
+		return fd, start, err
 	}
 	if r == '$' {
-		return t.parseRawDescriptor()
+		rd, err := t.parseRawDescriptor()
+		return rd, start, err
 	}
 
 	t.s.unread()
@@ -673,7 +689,7 @@ func (t *Parser) parseRaw() (ast.DataContent, error) {
 					t.buf.lit = ""
 				} else {
 					if r = t.s.read(); r != '#' {
-						return nil, ErrInvalidLogSection
+						return nil, start, ErrInvalidLogSection
 					}
 					// t.s.unread()
 					t.buf.tok = tokLOGSECTION
@@ -686,11 +702,15 @@ func (t *Parser) parseRaw() (ast.DataContent, error) {
 			}
 		}
 
+		if out.Len() == 0 {
+			start = t.astPos()
+		}
+
 		r := t.s.read()
 
 		if r == eof {
 			if inEscape {
-				return ast.NoContent{}, ErrOpenEscapeBlock
+				return ast.NoContent{}, start, ErrOpenEscapeBlock
 			}
 			t.s.unread()
 			break
@@ -717,10 +737,10 @@ func (t *Parser) parseRaw() (ast.DataContent, error) {
 
 	outStr := out.String()
 	if outStr == "" {
-		return ast.NoContent{}, nil
+		return ast.NoContent{}, start, nil
 	}
 
-	return ast.TextBlock{Content: outStr}, nil
+	return ast.TextBlock{Content: outStr}, start, nil
 }
 
 func (t *Parser) parseValue() (any, []ast.Comment, error) {
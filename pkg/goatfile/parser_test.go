@@ -144,6 +144,28 @@ password = "{{.creds.password}}"
 		}}, res.Actions[0].(*ast.Request).Blocks[3].(ast.RequestAuth))
 	})
 
+	t.Run("functions-block", func(t *testing.T) {
+		const raw = `
+GET https://example.com
+
+[Functions]
+exports.upper = (s) => s.toUpperCase();
+
+[Body]
+{{ upper "hello" }}
+`
+
+		p := stringParser(raw)
+		res, err := p.Parse()
+
+		assert.Nil(t, err, err)
+		req := res.Actions[0].(*ast.Request)
+		assert.Equal(t, ast.RequestFunctions{
+			DataContent: ast.TextBlock{Content: "exports.upper = (s) => s.toUpperCase();\n"},
+			Pos:         pos(38, 4, 0),
+		}, req.Blocks[0].(ast.RequestFunctions))
+	})
+
 	t.Run("single-file-value", func(t *testing.T) {
 		const raw = `
 		
@@ -1740,6 +1762,29 @@ execute ../pathTo/someGoatfile (
 	})
 }
 
+func TestParse_Positions(t *testing.T) {
+	raw := swapTicks(`
+GET  https://example.com
+
+[PreScript]
+´´´
+var a = 1;
+´´´
+
+[Script]
+assert(response.StatusCode === 200);
+`)
+
+	p := stringParser(raw)
+	res, err := p.Parse()
+	assert.Nil(t, err, err)
+
+	req := res.Actions[0].(*ast.Request)
+	assert.Equal(t, pos(6, 1, 5), req.Head.UrlPos)
+	assert.Equal(t, pos(43, 5, 0), req.Blocks[0].(ast.RequestPreScript).Pos)
+	assert.Equal(t, pos(68, 9, 0), req.Blocks[1].(ast.RequestScript).Pos)
+}
+
 // --- Helpers --------------------------------------------
 
 func stringParser(raw string) *Parser {
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"strings"
+	"text/template"
 
 	"github.com/studio-b12/goat/pkg/engine"
 	"github.com/studio-b12/goat/pkg/errs"
@@ -16,6 +18,10 @@ import (
 
 const conditionOptionName = "condition"
 
+// MethodGrpc is the request method used to
+// perform gRPC calls instead of HTTP requests.
+const MethodGrpc = "GRPC"
+
 // Request holds the specifications
 // for a HTTP request with options
 // and script commands.
@@ -28,10 +34,24 @@ type Request struct {
 	Body      Data
 	PreScript Data
 	Script    Data
+	Functions Data
+
+	// TemplateFuncs are additional functions available
+	// in templates when the request is substituted.
+	TemplateFuncs template.FuncMap
 
 	Path    string
 	PosLine int
 
+	// Positions of the URI and the contents of the
+	// Body, PreScript, Script and Functions blocks
+	// in their source files.
+	URIPos       SourcePos
+	BodyPos      SourcePos
+	PreScriptPos SourcePos
+	ScriptPos    SourcePos
+	FunctionsPos SourcePos
+
 	parsed    bool
 	preParsed bool
 }
@@ -44,6 +64,7 @@ func newRequest() (t *Request) {
 	t.Body = NoContent{}
 	t.PreScript = NoContent{}
 	t.Script = NoContent{}
+	t.Functions = NoContent{}
 	return t
 }
 
@@ -58,6 +79,9 @@ func RequestFromAst(req *ast.Request, path string) (t *Request, err error) {
 	t.Method = req.Head.Method
 	t.URI = req.Head.Url
 	t.PosLine = req.Pos.Line + 1 // TODO: actually, this should start counting at 0 and the printer should add 1
+	if req.Head.Url != "" {
+		t.URIPos = sourcePosFromAst(req.Head.UrlPos, path)
+	}
 
 	var additionalHeader http.Header
 
@@ -73,10 +97,16 @@ func RequestFromAst(req *ast.Request, path string) (t *Request, err error) {
 			t.Auth = b.KVList.ToMap()
 		case ast.RequestBody:
 			t.Body, additionalHeader, err = DataFromAst(b.DataContent, path)
+			t.BodyPos = dataSourcePos(t.Body, b.Pos, path)
 		case ast.RequestPreScript:
 			t.PreScript, _, err = DataFromAst(b.DataContent, path)
+			t.PreScriptPos = dataSourcePos(t.PreScript, b.Pos, path)
 		case ast.RequestScript:
 			t.Script, _, err = DataFromAst(b.DataContent, path)
+			t.ScriptPos = dataSourcePos(t.Script, b.Pos, path)
+		case ast.RequestFunctions:
+			t.Functions, _, err = DataFromAst(b.DataContent, path)
+			t.FunctionsPos = dataSourcePos(t.Functions, b.Pos, path)
 		case ast.FormData:
 			t.Body, additionalHeader, err = DataFromAst(b, path)
 		default:
@@ -123,9 +153,9 @@ func (t *Request) PreSubstituteWithParams(params any) error {
 		return errs.WithPrefix("reading preScript failed:", err)
 	}
 
-	preScriptStr, err = ApplyTemplate(preScriptStr, params)
+	preScriptStr, err = ApplyTemplate(preScriptStr, params, t.TemplateFuncs)
 	if err != nil {
-		return err
+		return templateError(err, t.PreScriptPos)
 	}
 	t.PreScript = StringContent(preScriptStr)
 
@@ -145,9 +175,9 @@ func (t *Request) SubstituteWithParams(params any) error {
 
 	// Substitute Options
 
-	err = ApplyTemplateToMap(t.Options, params)
+	err = ApplyTemplateToMap(t.Options, params, t.TemplateFuncs)
 	if err != nil {
-		return err
+		return sourceError(err, t.pos())
 	}
 
 	if v, ok := t.Options[conditionOptionName].(bool); ok && !v {
@@ -156,32 +186,32 @@ func (t *Request) SubstituteWithParams(params any) error {
 
 	// Substitute URI
 
-	t.URI, err = ApplyTemplate(t.URI, params)
+	t.URI, err = ApplyTemplate(t.URI, params, t.TemplateFuncs)
 	if err != nil {
-		return err
+		return templateError(err, t.URIPos)
 	}
 
 	// Substitute QueryParams
 
-	err = ApplyTemplateToMap(t.QueryParams, params)
+	err = ApplyTemplateToMap(t.QueryParams, params, t.TemplateFuncs)
 	if err != nil {
-		return err
+		return sourceError(err, t.pos())
 	}
 
 	// Substitute Auth
 
-	err = ApplyTemplateToMap(t.Auth, params)
+	err = ApplyTemplateToMap(t.Auth, params, t.TemplateFuncs)
 	if err != nil {
-		return err
+		return sourceError(err, t.pos())
 	}
 
 	// Substitute Header
 
 	for _, vals := range t.Header {
 		for i, v := range vals {
-			vals[i], err = ApplyTemplate(v, params)
+			vals[i], err = ApplyTemplate(v, params, t.TemplateFuncs)
 			if err != nil {
-				return err
+				return sourceError(err, t.pos())
 			}
 		}
 	}
@@ -190,21 +220,21 @@ func (t *Request) SubstituteWithParams(params any) error {
 
 	switch body := t.Body.(type) {
 	case StringContent:
-		bodyStr, err := ApplyTemplate(string(body), params)
+		bodyStr, err := ApplyTemplate(string(body), params, t.TemplateFuncs)
 		if err != nil {
-			return err
+			return templateError(err, t.BodyPos)
 		}
 		t.Body = StringContent(bodyStr)
 	case FileContent:
-		body.filePath, err = ApplyTemplate(body.filePath, params)
+		body.filePath, err = ApplyTemplate(body.filePath, params, t.TemplateFuncs)
 		if err != nil {
-			return err
+			return sourceError(err, t.pos())
 		}
 		t.Body = body
 	case FormData:
-		err = ApplyTemplateToMap(body.fields, params)
+		err = ApplyTemplateToMap(body.fields, params, t.TemplateFuncs)
 		if err != nil {
-			return err
+			return sourceError(err, t.pos())
 		}
 		t.Body = body
 	}
@@ -216,9 +246,9 @@ func (t *Request) SubstituteWithParams(params any) error {
 		return errs.WithPrefix("reading script failed:", err)
 	}
 
-	scriptStr, err = ApplyTemplate(scriptStr, params)
+	scriptStr, err = ApplyTemplate(scriptStr, params, t.TemplateFuncs)
 	if err != nil {
-		return err
+		return templateError(err, t.ScriptPos)
 	}
 	t.Script = StringContent(scriptStr)
 
@@ -314,6 +344,11 @@ func (t *Request) ToHttpRequest() (*http.Request, error) {
 	return req, nil
 }
 
+// IsGrpc returns true if the request method is GRPC.
+func (t *Request) IsGrpc() bool {
+	return strings.EqualFold(t.Method, MethodGrpc)
+}
+
 func (t *Request) Merge(with *Request) {
 	if t == nil || with == nil {
 		return
@@ -343,15 +378,58 @@ func (t *Request) Merge(with *Request) {
 
 	if IsNoContent(t.Body) && !IsNoContent(with.Body) {
 		t.Body = with.Body
+		t.BodyPos = with.BodyPos
 	}
 
 	if IsNoContent(t.PreScript) && !IsNoContent(with.PreScript) {
 		t.PreScript = with.PreScript
+		t.PreScriptPos = with.PreScriptPos
 	}
 
 	if IsNoContent(t.Script) && !IsNoContent(with.Script) {
 		t.Script = with.Script
+		t.ScriptPos = with.ScriptPos
 	}
+
+	if IsNoContent(t.Functions) && !IsNoContent(with.Functions) {
+		t.Functions = with.Functions
+		t.FunctionsPos = with.FunctionsPos
+	}
+}
+
+// Copy returns a deep copy of the request, so that
+// the copy can be substituted without modifying the
+// original request.
+func (t *Request) Copy() *Request {
+	if t == nil {
+		return nil
+	}
+
+	cp := *t
+	cp.QueryParams = copyMap(t.QueryParams)
+	cp.Options = copyMap(t.Options)
+	cp.Auth = copyMap(t.Auth)
+	cp.Header = t.Header.Clone()
+
+	if body, ok := t.Body.(FormData); ok {
+		body.fields = copyMap(body.fields)
+		cp.Body = body
+	}
+
+	if t.TemplateFuncs != nil {
+		cp.TemplateFuncs = make(template.FuncMap, len(t.TemplateFuncs))
+		for name, fn := range t.TemplateFuncs {
+			cp.TemplateFuncs[name] = fn
+		}
+	}
+
+	return &cp
+}
+
+// pos returns the position of the request
+// in its source file.
+func (t *Request) pos() SourcePos {
+	return SourcePos{File: t.Path, Line: t.PosLine}
 }
 
 func (t *Request) String() string {
@@ -16,8 +16,8 @@ func TestRequestFromAst(t *testing.T) {
 		},
 		Blocks: []ast.RequestBlock{
 			ast.RequestOptions{ast.KVList[any]{ast.KV[any]{Key: "a", Value: "b"}}},
-			ast.RequestBody{ast.TextBlock{"body stuff"}},
-			ast.RequestScript{ast.TextBlock{"script stuff"}},
+			ast.RequestBody{DataContent: ast.TextBlock{"body stuff"}},
+			ast.RequestScript{DataContent: ast.TextBlock{"script stuff"}},
 		},
 	}
 
@@ -37,8 +37,8 @@ func TestPartialRequestFromAst(t *testing.T) {
 	astR := ast.PartialRequest{
 		Blocks: []ast.RequestBlock{
 			ast.RequestOptions{ast.KVList[any]{ast.KV[any]{Key: "a", Value: "b"}}},
-			ast.RequestBody{ast.TextBlock{"body stuff"}},
-			ast.RequestScript{ast.TextBlock{"script stuff"}},
+			ast.RequestBody{DataContent: ast.TextBlock{"body stuff"}},
+			ast.RequestScript{DataContent: ast.TextBlock{"script stuff"}},
 		},
 	}
 
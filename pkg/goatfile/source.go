@@ -0,0 +1,125 @@
+package goatfile
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/goatfile/ast"
+)
+
+// templateErrorPosition matches the position in the
+// context of errors returned by text/template.
+var templateErrorPosition = regexp.MustCompile(`template: :(\d+)(?::(\d+))?:`)
+
+// SourcePos is a position in a source file.
+type SourcePos struct {
+	File string
+
+	// Line and Column of the position, starting
+	// at 1. Column is 0 if it is unknown.
+	Line   int
+	Column int
+}
+
+func sourcePosFromAst(pos ast.Pos, file string) SourcePos {
+	return SourcePos{
+		File:   file,
+		Line:   pos.Line + 1,
+		Column: pos.LinePos + 1,
+	}
+}
+
+// IsZero returns true if the position is not set.
+func (t SourcePos) IsZero() bool {
+	return t.Line == 0
+}
+
+// Offset returns the position of the given line and column
+// in a content starting at t. Both line and column start
+// at 1. If column is 0, the returned column is 0 as well.
+func (t SourcePos) Offset(line, column int) SourcePos {
+	pos := SourcePos{File: t.File, Line: t.Line + line - 1}
+
+	switch {
+	case column == 0:
+	case line == 1 && t.Column > 0:
+		pos.Column = t.Column + column - 1
+	default:
+		pos.Column = column
+	}
+
+	return pos
+}
+
+func (t SourcePos) String() string {
+	if t.Column == 0 {
+		return fmt.Sprintf("%s:%d", t.File, t.Line)
+	}
+	return fmt.Sprintf("%s:%d:%d", t.File, t.Line, t.Column)
+}
+
+// SourceError wraps an error which occurred at
+// the given position in a source file.
+type SourceError struct {
+	errs.InnerError
+
+	Pos SourcePos
+}
+
+// sourceError wraps the given error with the position
+// in the source file it occurred at. If pos is not set,
+// err is returned as is.
+func sourceError(err error, pos SourcePos) error {
+	if pos.IsZero() {
+		return err
+	}
+
+	var t SourceError
+	t.Inner = err
+	t.Pos = pos
+	return &t
+}
+
+// templateError wraps the given error returned when
+// applying a template with the position in the source
+// file it occurred at. pos is the position the templated
+// content starts at.
+func templateError(err error, pos SourcePos) error {
+	if pos.IsZero() {
+		return err
+	}
+
+	if match := templateErrorPosition.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		column := 0
+		if match[2] != "" {
+			// Columns in template errors start at 0.
+			column, _ = strconv.Atoi(match[2])
+			column++
+		}
+		pos = pos.Offset(line, column)
+	}
+
+	return sourceError(err, pos)
+}
+
+// dataSourcePos returns the position of the content of
+// the given data. For text blocks, this is the position
+// of the block content in the Goatfile. For file contents,
+// it is the start of the referenced file.
+func dataSourcePos(data Data, pos ast.Pos, path string) SourcePos {
+	switch d := data.(type) {
+	case StringContent:
+		return sourcePosFromAst(pos, path)
+	case FileContent:
+		filePath, err := joinPath(d.currDir, d.filePath)
+		if err != nil {
+			return SourcePos{}
+		}
+		return SourcePos{File: filePath, Line: 1, Column: 1}
+	default:
+		return SourcePos{}
+	}
+}
@@ -0,0 +1,68 @@
+package goatfile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+func TestSourcePos_Offset(t *testing.T) {
+	start := SourcePos{File: "test.goat", Line: 10, Column: 5}
+
+	assert.Equal(t, SourcePos{File: "test.goat", Line: 10, Column: 7}, start.Offset(1, 3))
+	assert.Equal(t, SourcePos{File: "test.goat", Line: 12, Column: 3}, start.Offset(3, 3))
+	assert.Equal(t, SourcePos{File: "test.goat", Line: 11, Column: 0}, start.Offset(2, 0))
+}
+
+func TestSourcePos_String(t *testing.T) {
+	assert.Equal(t, "test.goat:10:5", SourcePos{File: "test.goat", Line: 10, Column: 5}.String())
+	assert.Equal(t, "test.goat:10", SourcePos{File: "test.goat", Line: 10}.String())
+}
+
+func TestSubstituteWithParams_SourcePos(t *testing.T) {
+	raw := `
+GET https://example.com/{{.id}}
+
+[Body]
+{
+  "name": "{{.name}}"
+}
+
+[Script]
+assert(response.StatusCode === {{.status}});
+`
+
+	substitute := func(params map[string]any) error {
+		gf, err := Unmarshal(raw, "test.goat")
+		assert.Nil(t, err, err)
+		return gf.Tests[0].(*Request).SubstituteWithParams(params)
+	}
+
+	t.Run("uri", func(t *testing.T) {
+		err := substitute(map[string]any{})
+		srcErr, ok := errs.As[*SourceError](err)
+		assert.True(t, ok, err)
+		assert.Equal(t, SourcePos{File: "test.goat", Line: 2, Column: 27}, srcErr.Pos)
+	})
+
+	t.Run("body", func(t *testing.T) {
+		err := substitute(map[string]any{"id": 1})
+		srcErr, ok := errs.As[*SourceError](err)
+		assert.True(t, ok, err)
+		assert.Equal(t, SourcePos{File: "test.goat", Line: 6, Column: 14}, srcErr.Pos)
+	})
+
+	t.Run("script", func(t *testing.T) {
+		err := substitute(map[string]any{"id": 1, "name": "foo"})
+		srcErr, ok := errs.As[*SourceError](err)
+		assert.True(t, ok, err)
+		assert.Equal(t, SourcePos{File: "test.goat", Line: 10, Column: 34}, srcErr.Pos)
+	})
+}
+
+func TestTemplateError_NoPos(t *testing.T) {
+	err := errors.New("template: :1:2: some error")
+	assert.Equal(t, err, templateError(err, SourcePos{}))
+}
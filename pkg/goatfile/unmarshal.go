@@ -58,6 +58,8 @@ func unmarshal(fSys fs.FS, raw string, fileDir string, visited set.Set[string])
 		}
 
 		imports.Merge(importGf)
+		imports.Imports = append(imports.Imports, fullPath)
+		imports.Imports = append(imports.Imports, importGf.Imports...)
 	}
 
 	imports.Merge(gf)
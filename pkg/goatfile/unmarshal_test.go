@@ -36,6 +36,7 @@ GET https://example3.com
 	gf, err := unmarshal(mockFs, contentA, "test/test.goat", set.Set[string]{})
 	assert.Nil(t, err)
 	assert.Equal(t, Goatfile{
+		Imports: []string{"test/b/b.goat", "test/c.goat"},
 		Tests: []Action{
 			testRequestWithPath("GET", "https://example3.com", "test/c.goat", 2),
 			testRequestWithPath("GET", "https://example2.com", "test/b/b.goat", 4),
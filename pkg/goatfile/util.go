@@ -16,9 +16,17 @@ import (
 //
 // If a key in the template is not present in the params,
 // an error will be returned.
-func ApplyTemplateBuf(raw string, params any) (*bytes.Buffer, error) {
-	tmpl, err := template.New("").
-		Funcs(builtinFuncsMap).
+//
+// Additional template functions can be passed via funcs.
+// These take precedence over builtin functions with the
+// same name.
+func ApplyTemplateBuf(raw string, params any, funcs ...template.FuncMap) (*bytes.Buffer, error) {
+	tmpl := template.New("").Funcs(builtinFuncsMap)
+	for _, f := range funcs {
+		tmpl.Funcs(f)
+	}
+
+	tmpl, err := tmpl.
 		Option("missingkey=error").
 		Parse(raw)
 	if err != nil {
@@ -40,12 +48,12 @@ func ApplyTemplateBuf(raw string, params any) (*bytes.Buffer, error) {
 //
 // If a key in the template is not present in the params,
 // an error will be returned.
-func ApplyTemplate(raw string, params any) (string, error) {
+func ApplyTemplate(raw string, params any, funcs ...template.FuncMap) (string, error) {
 	if m, ok := params.(map[string]any); ok {
 		transformPrintable(m)
 	}
 
-	out, err := ApplyTemplateBuf(raw, params)
+	out, err := ApplyTemplateBuf(raw, params, funcs...)
 	if err != nil {
 		return "", err
 	}
@@ -57,13 +65,13 @@ func ApplyTemplate(raw string, params any) (string, error) {
 // ApplyTemplateToArray executes applyTemplate
 // on all string instances in the given array
 // or sub arrays.
-func ApplyTemplateToArray(arr []any, params any) (err error) {
+func ApplyTemplateToArray(arr []any, params any, funcs ...template.FuncMap) (err error) {
 	for i, v := range arr {
 		switch vt := v.(type) {
 		case string:
-			arr[i], err = ApplyTemplate(vt, params)
+			arr[i], err = ApplyTemplate(vt, params, funcs...)
 		case []any:
-			err = ApplyTemplateToArray(vt, params)
+			err = ApplyTemplateToArray(vt, params, funcs...)
 		default:
 			continue
 		}
@@ -76,19 +84,58 @@ func ApplyTemplateToArray(arr []any, params any) (err error) {
 	return nil
 }
 
+// copyArray returns a copy of the given array
+// including copies of all sub arrays and maps.
+func copyArray(arr []any) []any {
+	if arr == nil {
+		return nil
+	}
+
+	cp := make([]any, len(arr))
+	for i, v := range arr {
+		cp[i] = copyValue(v)
+	}
+	return cp
+}
+
+// copyMap returns a copy of the given map
+// including copies of all sub arrays and maps.
+func copyMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+
+	cp := make(map[string]any, len(m))
+	for k, v := range m {
+		cp[k] = copyValue(v)
+	}
+	return cp
+}
+
+func copyValue(v any) any {
+	switch vt := v.(type) {
+	case []any:
+		return copyArray(vt)
+	case map[string]any:
+		return copyMap(vt)
+	default:
+		return v
+	}
+}
+
 // ApplyTemplateToMap executes applyTemplate
 // on all values in the given map.
-func ApplyTemplateToMap(m map[string]any, params any) (err error) {
+func ApplyTemplateToMap(m map[string]any, params any, funcs ...template.FuncMap) (err error) {
 	for k, v := range m {
 		switch vt := v.(type) {
 		case ParameterValue:
-			m[k], err = vt.ApplyTemplate(params)
+			m[k], err = vt.ApplyTemplate(params, funcs...)
 		case string:
-			m[k], err = ApplyTemplate(vt, params)
+			m[k], err = ApplyTemplate(vt, params, funcs...)
 		case []any:
-			err = ApplyTemplateToArray(vt, params)
+			err = ApplyTemplateToArray(vt, params, funcs...)
 		case map[string]any:
-			err = ApplyTemplateToMap(vt, params)
+			err = ApplyTemplateToMap(vt, params, funcs...)
 		default:
 			continue
 		}
@@ -0,0 +1,266 @@
+package grpcrequester
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/studio-b12/goat/pkg/errs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Client implements Requester using grpc-go. Connections
+// and resolved descriptors are cached for the lifetime
+// of the Client.
+type Client struct {
+	tlsConfig *tls.Config
+
+	mtx         sync.Mutex
+	conns       map[string]*grpc.ClientConn
+	descriptors map[string]resolver
+}
+
+var _ Requester = (*Client)(nil)
+
+// NewClient returns a new instance of Client. The given
+// tlsConfig is used for connections to secure targets.
+// If tlsConfig is nil, the default TLS configuration is
+// used.
+func NewClient(tlsConfig *tls.Config) *Client {
+	var t Client
+
+	t.tlsConfig = tlsConfig
+	if t.tlsConfig == nil {
+		t.tlsConfig = &tls.Config{}
+	}
+
+	t.conns = make(map[string]*grpc.ClientConn)
+	t.descriptors = make(map[string]resolver)
+
+	return &t
+}
+
+func (t *Client) Invoke(ctx context.Context, req Request, opt Options) (*Response, error) {
+	if opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opt.Timeout)
+		defer cancel()
+	}
+
+	conn, err := t.getConn(req.Target, req.Secure)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.getResolver(ctx, conn, req, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := findMethod(res, req.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	in := dynamicpb.NewMessage(method.Input())
+	if len(req.Body) > 0 {
+		err = protojson.Unmarshal(req.Body, in)
+		if err != nil {
+			return nil, errs.WithPrefix("failed decoding request message:", err)
+		}
+	}
+
+	newOut := func() proto.Message { return dynamicpb.NewMessage(method.Output()) }
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+
+	ctx = metadata.NewOutgoingContext(ctx, toMetadata(req.Header))
+
+	logger.Trace().Fields(
+		"target", req.Target,
+		"method", fullMethod,
+		"header", req.Header,
+	).Msg("Sending gRPC request ...")
+
+	var (
+		resp    Response
+		header  metadata.MD
+		trailer metadata.MD
+		msgs    []proto.Message
+	)
+
+	resp.Streaming = method.IsStreamingServer()
+
+	if resp.Streaming {
+		msgs, err = t.invokeServerStream(ctx, conn, fullMethod, in, opt, newOut, &header, &trailer)
+	} else {
+		out := newOut()
+		err = conn.Invoke(ctx, fullMethod, in, out, grpc.Header(&header), grpc.Trailer(&trailer))
+		if err == nil {
+			msgs = append(msgs, out)
+		}
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, err
+	}
+
+	resp.Code = int(st.Code())
+	resp.Status = st.Code().String()
+	resp.Message = st.Message()
+	resp.Header = header
+	resp.Trailer = trailer
+
+	for _, msg := range msgs {
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, errs.WithPrefix("failed encoding response message:", err)
+		}
+		resp.Messages = append(resp.Messages, data)
+	}
+
+	logger.Trace().Fields(
+		"status", resp.Status,
+		"header", resp.Header,
+	).Msg("Received gRPC response")
+
+	return &resp, nil
+}
+
+func (t *Client) invokeServerStream(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	fullMethod string,
+	in proto.Message,
+	opt Options,
+	newOut func() proto.Message,
+	header, trailer *metadata.MD,
+) ([]proto.Message, error) {
+	var (
+		streamCtx context.Context
+		cancel    context.CancelFunc
+	)
+	if opt.StreamTimeout > 0 {
+		streamCtx, cancel = context.WithTimeout(ctx, opt.StreamTimeout)
+	} else {
+		streamCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	stream, err := conn.NewStream(streamCtx, &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = stream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	msgs, err := recvAll(stream, opt.MaxMessages, newOut)
+
+	*header, _ = stream.Header()
+	*trailer = stream.Trailer()
+
+	// Reaching the stream timeout only stops reading
+	// the stream and is not treated as error.
+	if err != nil && ctx.Err() == nil && streamCtx.Err() == context.DeadlineExceeded {
+		err = nil
+	}
+
+	return msgs, err
+}
+
+func (t *Client) getConn(target string, secure bool) (*grpc.ClientConn, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	key := fmt.Sprintf("%s|%t", target, secure)
+	if conn, ok := t.conns[key]; ok {
+		return conn, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if secure {
+		creds = credentials.NewTLS(t.tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errs.WithPrefix("failed creating grpc client:", err)
+	}
+
+	t.conns[key] = conn
+	return conn, nil
+}
+
+func (t *Client) getResolver(ctx context.Context, conn *grpc.ClientConn, req Request, opt Options) (res resolver, err error) {
+	var key string
+	if len(opt.ProtoFiles) > 0 {
+		files := append([]string{}, opt.ProtoFiles...)
+		sort.Strings(files)
+		key = "files|" + strings.Join(files, "|") + "|" + strings.Join(opt.ImportPaths, "|")
+	} else {
+		service, _, err := splitMethod(req.Method)
+		if err != nil {
+			return nil, err
+		}
+		key = fmt.Sprintf("reflection|%s|%t|%s", req.Target, req.Secure, service)
+	}
+
+	t.mtx.Lock()
+	res, ok := t.descriptors[key]
+	t.mtx.Unlock()
+	if ok {
+		return res, nil
+	}
+
+	if len(opt.ProtoFiles) > 0 {
+		res, err = compileProtoFiles(ctx, opt.ProtoFiles, opt.ImportPaths)
+	} else {
+		service, _, _ := splitMethod(req.Method)
+		res, err = reflectService(ctx, conn, service)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t.mtx.Lock()
+	t.descriptors[key] = res
+	t.mtx.Unlock()
+
+	return res, nil
+}
+
+// Close closes all open connections.
+func (t *Client) Close() (err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for key, conn := range t.conns {
+		err = errs.Join(err, conn.Close())
+		delete(t.conns, key)
+	}
+
+	return err
+}
+
+func toMetadata(header map[string][]string) metadata.MD {
+	md := metadata.MD{}
+	for k, vals := range header {
+		md.Append(k, vals...)
+	}
+	return md
+}
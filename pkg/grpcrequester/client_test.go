@@ -0,0 +1,240 @@
+package grpcrequester
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestClient(t *testing.T) {
+	target := startTestServer(t)
+
+	t.Run("unary-protofiles", func(t *testing.T) {
+		c := NewClient(nil)
+		defer c.Close()
+
+		res, err := c.Invoke(context.Background(), Request{
+			Target: target,
+			Method: "goat.test.Greeter/SayHello",
+			Header: http.Header{"X-Name-Suffix": {"!"}},
+			Body:   []byte(`{"name": "goat"}`),
+		}, Options{
+			ProtoFiles:  []string{"greeter.proto"},
+			ImportPaths: []string{"testdata"},
+		})
+		require.Nil(t, err)
+
+		assert.Equal(t, 0, res.Code)
+		assert.Equal(t, "OK", res.Status)
+		assert.False(t, res.Streaming)
+		assert.Equal(t, []string{"bar"}, res.Header["x-foo"])
+		require.Len(t, res.Messages, 1)
+		assert.Equal(t, map[string]any{"message": "Hello, goat!"}, decode(t, res.Messages[0]))
+	})
+
+	t.Run("unary-reflection", func(t *testing.T) {
+		c := NewClient(nil)
+		defer c.Close()
+
+		res, err := c.Invoke(context.Background(), Request{
+			Target: target,
+			Method: "goat.test.Greeter/SayHello",
+			Body:   []byte(`{"name": "goat"}`),
+		}, Options{})
+		require.Nil(t, err)
+
+		require.Len(t, res.Messages, 1)
+		assert.Equal(t, map[string]any{"message": "Hello, goat"}, decode(t, res.Messages[0]))
+	})
+
+	t.Run("unary-status", func(t *testing.T) {
+		c := NewClient(nil)
+		defer c.Close()
+
+		res, err := c.Invoke(context.Background(), Request{
+			Target: target,
+			Method: "goat.test.Greeter/SayHello",
+			Body:   []byte(`{}`),
+		}, Options{})
+		require.Nil(t, err)
+
+		assert.Equal(t, int(codes.InvalidArgument), res.Code)
+		assert.Equal(t, "InvalidArgument", res.Status)
+		assert.Equal(t, "name is empty", res.Message)
+		assert.Empty(t, res.Messages)
+	})
+
+	t.Run("server-streaming", func(t *testing.T) {
+		c := NewClient(nil)
+		defer c.Close()
+
+		res, err := c.Invoke(context.Background(), Request{
+			Target: target,
+			Method: "goat.test.Greeter/Count",
+			Body:   []byte(`{"to": 5}`),
+		}, Options{})
+		require.Nil(t, err)
+
+		assert.True(t, res.Streaming)
+		require.Len(t, res.Messages, 5)
+		assert.Equal(t, map[string]any{"n": 3.0}, decode(t, res.Messages[2]))
+	})
+
+	t.Run("server-streaming-max", func(t *testing.T) {
+		c := NewClient(nil)
+		defer c.Close()
+
+		res, err := c.Invoke(context.Background(), Request{
+			Target: target,
+			Method: "goat.test.Greeter/Count",
+			Body:   []byte(`{"to": 5}`),
+		}, Options{MaxMessages: 2})
+		require.Nil(t, err)
+
+		assert.Len(t, res.Messages, 2)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		c := NewClient(nil)
+		defer c.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := c.Invoke(ctx, Request{
+			Target: target,
+			Method: "goat.test.Greeter/SayHello",
+			Body:   []byte(`{"name": "goat"}`),
+		}, Options{
+			ProtoFiles:  []string{"greeter.proto"},
+			ImportPaths: []string{"testdata"},
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("invalid-method", func(t *testing.T) {
+		c := NewClient(nil)
+		defer c.Close()
+
+		_, err := c.Invoke(context.Background(), Request{
+			Target: target,
+			Method: "goat.test.Greeter",
+		}, Options{})
+		assert.ErrorIs(t, err, ErrInvalidMethodName)
+
+		_, err = c.Invoke(context.Background(), Request{
+			Target: target,
+			Method: "goat.test.Greeter/Nope",
+		}, Options{})
+		assert.ErrorIs(t, err, ErrMethodNotFound)
+	})
+}
+
+func decode(t *testing.T, data []byte) any {
+	t.Helper()
+	var v any
+	require.Nil(t, json.Unmarshal(data, &v))
+	return v
+}
+
+// startTestServer starts an in-process gRPC server implementing the
+// Greeter service defined in testdata/greeter.proto with server
+// reflection enabled and returns the target address.
+func startTestServer(t *testing.T) string {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: []string{"testdata"},
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), "greeter.proto")
+	require.Nil(t, err)
+
+	service := files[0].Services().ByName("Greeter")
+	sayHello := service.Methods().ByName("SayHello")
+	count := service.Methods().ByName("Count")
+
+	desc := grpc.ServiceDesc{
+		ServiceName: string(service.FullName()),
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: string(sayHello.Name()),
+			Handler: func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				in := dynamicpb.NewMessage(sayHello.Input())
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+
+				name := in.Get(sayHello.Input().Fields().ByName("name")).String()
+				if name == "" {
+					return nil, status.Error(codes.InvalidArgument, "name is empty")
+				}
+
+				md, _ := metadata.FromIncomingContext(ctx)
+				suffix := ""
+				if v := md.Get("x-name-suffix"); len(v) > 0 {
+					suffix = v[0]
+				}
+
+				grpc.SetHeader(ctx, metadata.Pairs("x-foo", "bar"))
+
+				out := dynamicpb.NewMessage(sayHello.Output())
+				out.Set(sayHello.Output().Fields().ByName("message"),
+					protoreflect.ValueOfString(fmt.Sprintf("Hello, %s%s", name, suffix)))
+				return out, nil
+			},
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    string(count.Name()),
+			ServerStreams: true,
+			Handler: func(_ any, stream grpc.ServerStream) error {
+				in := dynamicpb.NewMessage(count.Input())
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+
+				to := in.Get(count.Input().Fields().ByName("to")).Int()
+				for i := int64(1); i <= to; i++ {
+					out := dynamicpb.NewMessage(count.Output())
+					out.Set(count.Output().Fields().ByName("n"), protoreflect.ValueOfInt32(int32(i)))
+					if err := stream.SendMsg(out); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}},
+	}
+
+	srv := grpc.NewServer()
+	srv.RegisterService(&desc, struct{}{})
+	rpb.RegisterServerReflectionServer(srv, reflection.NewServerV1(reflection.ServerOptions{
+		Services:           srv,
+		DescriptorResolver: linker.Files(files).AsResolver(),
+	}))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
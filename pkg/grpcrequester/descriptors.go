@@ -0,0 +1,203 @@
+package grpcrequester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/studio-b12/goat/pkg/errs"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var (
+	ErrInvalidMethodName = errors.New("invalid method name, must be in format 'package.Service/Method'")
+	ErrNotAService       = errors.New("descriptor is not a service")
+	ErrMethodNotFound    = errors.New("method not found in service")
+	ErrClientStreaming   = errors.New("client streaming methods are not supported")
+)
+
+// resolver finds descriptors by their full name.
+type resolver interface {
+	FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error)
+}
+
+// splitMethod splits the given method name in the
+// format 'package.Service/Method' into the service
+// and method name.
+func splitMethod(name string) (service, method string, err error) {
+	name = strings.TrimPrefix(name, "/")
+	service, method, ok := strings.Cut(name, "/")
+	if !ok || service == "" || method == "" || strings.Contains(method, "/") {
+		return "", "", ErrInvalidMethodName
+	}
+	return service, method, nil
+}
+
+// findMethod looks up the method descriptor for the given
+// method name in the format 'package.Service/Method' using
+// the passed resolver.
+func findMethod(res resolver, name string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := splitMethod(name)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := res.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, errs.WithPrefix(fmt.Sprintf("failed finding service %s:", serviceName), err)
+	}
+
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, errs.WithSuffix(ErrNotAService, fmt.Sprintf("(%s)", serviceName))
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, errs.WithSuffix(ErrMethodNotFound, fmt.Sprintf("(%s)", name))
+	}
+
+	if method.IsStreamingClient() {
+		return nil, errs.WithSuffix(ErrClientStreaming, fmt.Sprintf("(%s)", name))
+	}
+
+	return method, nil
+}
+
+// compileProtoFiles parses and links the given .proto files
+// using the given import paths to resolve imports.
+func compileProtoFiles(ctx context.Context, files, importPaths []string) (resolver, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: importPaths,
+		}),
+	}
+
+	linked, err := compiler.Compile(ctx, files...)
+	if err != nil {
+		return nil, errs.WithPrefix("failed compiling proto files:", err)
+	}
+
+	return linked.AsResolver(), nil
+}
+
+// reflectService retrieves the file descriptors containing the
+// given service and all its dependencies from the server
+// reflection service of the given connection.
+func reflectService(ctx context.Context, conn grpc.ClientConnInterface, service string) (resolver, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, errs.WithPrefix("failed opening server reflection stream:", err)
+	}
+	defer stream.CloseSend()
+
+	fdps := make(map[string]*descriptorpb.FileDescriptorProto)
+
+	request := func(req *rpb.ServerReflectionRequest) error {
+		err := stream.Send(req)
+		if err != nil {
+			return err
+		}
+
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		if errRes := res.GetErrorResponse(); errRes != nil {
+			return fmt.Errorf("server reflection error (%d): %s",
+				errRes.GetErrorCode(), errRes.GetErrorMessage())
+		}
+
+		for _, raw := range res.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			var fdp descriptorpb.FileDescriptorProto
+			if err = proto.Unmarshal(raw, &fdp); err != nil {
+				return err
+			}
+			fdps[fdp.GetName()] = &fdp
+		}
+
+		return nil
+	}
+
+	err = request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: service,
+		},
+	})
+	if err != nil {
+		return nil, errs.WithPrefix(fmt.Sprintf("failed reflecting service %s:", service), err)
+	}
+
+	// Servers usually respond with all transitive dependencies
+	// of the requested file. If not, the missing files are
+	// requested subsequently.
+	for {
+		missing := missingDependency(fdps)
+		if missing == "" {
+			break
+		}
+		err = request(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{
+				FileByFilename: missing,
+			},
+		})
+		if err != nil {
+			return nil, errs.WithPrefix(fmt.Sprintf("failed reflecting file %s:", missing), err)
+		}
+		if _, ok := fdps[missing]; !ok {
+			return nil, fmt.Errorf("server reflection did not return file %s", missing)
+		}
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	for _, fdp := range fdps {
+		set.File = append(set.File, fdp)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, errs.WithPrefix("failed building reflected file descriptors:", err)
+	}
+
+	return files, nil
+}
+
+func missingDependency(fdps map[string]*descriptorpb.FileDescriptorProto) string {
+	for _, fdp := range fdps {
+		for _, dep := range fdp.GetDependency() {
+			if _, ok := fdps[dep]; !ok {
+				return dep
+			}
+		}
+	}
+	return ""
+}
+
+// recvAll receives messages from the given stream until the stream
+// has ended or max messages have been received, if max is greater
+// than 0. newMsg is used to create a new message instance for
+// each received message.
+func recvAll(stream grpc.ClientStream, max int, newMsg func() proto.Message) ([]proto.Message, error) {
+	var msgs []proto.Message
+	for max <= 0 || len(msgs) < max {
+		msg := newMsg()
+		err := stream.RecvMsg(msg)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,66 @@
+package grpcrequester
+
+import (
+	"time"
+
+	"github.com/studio-b12/goat/pkg/util"
+)
+
+// Options wraps call specific options.
+type Options struct {
+	// ProtoFiles contains paths to .proto files from
+	// which the method descriptors are resolved. If
+	// empty, server reflection is used.
+	ProtoFiles []string
+	// ImportPaths are used to resolve imports
+	// in the given ProtoFiles.
+	ImportPaths []string
+	// MaxMessages limits the amount of messages read
+	// from a server stream, if greater than 0.
+	MaxMessages int
+	// Timeout limits the duration of a call,
+	// if greater than 0.
+	Timeout time.Duration
+	// StreamTimeout stops reading messages from a
+	// server stream after the given duration, if
+	// greater than 0.
+	StreamTimeout time.Duration
+}
+
+// OptionsFromMap takes a map and builds an
+// Options instance from matching key-value
+// pairs.
+func OptionsFromMap(m map[string]any) Options {
+	var opt Options
+
+	opt.ProtoFiles = util.StringList(m["protofiles"])
+	opt.ImportPaths = util.StringList(m["importpaths"])
+
+	switch v := m["maxevents"].(type) {
+	case int:
+		opt.MaxMessages = v
+	case int64:
+		opt.MaxMessages = int(v)
+	}
+
+	opt.Timeout = duration(m["grpctimeout"])
+	opt.StreamTimeout = duration(m["streamtimeout"])
+
+	return opt
+}
+
+// duration returns the given value as duration. Numbers
+// are interpreted as milliseconds and strings are parsed
+// as Go durations.
+func duration(v any) time.Duration {
+	switch vt := v.(type) {
+	case int:
+		return time.Duration(vt) * time.Millisecond
+	case int64:
+		return time.Duration(vt) * time.Millisecond
+	case string:
+		d, _ := time.ParseDuration(vt)
+		return d
+	}
+	return 0
+}
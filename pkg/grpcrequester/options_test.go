@@ -0,0 +1,28 @@
+package grpcrequester
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsFromMap(t *testing.T) {
+	opt := OptionsFromMap(map[string]any{
+		"protofiles":    "greeter.proto",
+		"importpaths":   []any{"protos", "vendor"},
+		"maxevents":     int64(3),
+		"grpctimeout":   "2s",
+		"streamtimeout": int64(500),
+	})
+
+	assert.Equal(t, Options{
+		ProtoFiles:    []string{"greeter.proto"},
+		ImportPaths:   []string{"protos", "vendor"},
+		MaxMessages:   3,
+		Timeout:       2 * time.Second,
+		StreamTimeout: 500 * time.Millisecond,
+	}, opt)
+
+	assert.Equal(t, Options{}, OptionsFromMap(map[string]any{}))
+}
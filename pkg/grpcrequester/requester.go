@@ -0,0 +1,60 @@
+// Package grpcrequester provides a service to perform
+// gRPC calls with request and response messages encoded
+// as JSON. Method descriptors are either resolved from
+// .proto files or via server reflection.
+package grpcrequester
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/zekrotja/rogu/log"
+)
+
+var logger = log.Tagged("grpcrequester")
+
+// Requester defines a service to perform
+// gRPC calls.
+type Requester interface {
+	// Invoke performs the given gRPC call and
+	// returns the response.
+	Invoke(ctx context.Context, req Request, opt Options) (*Response, error)
+}
+
+// Request describes a single gRPC call.
+type Request struct {
+	// Target is the address of the gRPC server
+	// in the format 'host:port'.
+	Target string
+	// Secure specifies whether TLS shall be used
+	// for the connection.
+	Secure bool
+	// Method is the full name of the method in the
+	// format 'package.Service/Method'.
+	Method string
+	// Header is sent as request metadata.
+	Header http.Header
+	// Body is the JSON encoded request message.
+	Body []byte
+}
+
+// Response holds the result of a gRPC call.
+type Response struct {
+	// Code is the gRPC status code of the call.
+	Code int
+	// Status is the string representation
+	// of the status code.
+	Status string
+	// Message is the status message.
+	Message string
+	Header  map[string][]string
+	Trailer map[string][]string
+	// Messages holds the JSON encoded response messages.
+	// Unary calls always result in one message, if they
+	// succeed. Server streaming calls may result in any
+	// amount of messages.
+	Messages [][]byte
+	// Streaming is true if the called method
+	// is server streaming.
+	Streaming bool
+}
@@ -0,0 +1,175 @@
+package repl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+const (
+	blockDelimiter   = "```"
+	requestDelimiter = "---"
+)
+
+// entry is an input of the session which has
+// been executed.
+type entry struct {
+	raw    string
+	script bool
+	gf     goatfile.Goatfile
+}
+
+// export builds a Goatfile from the given entries.
+//
+// Goatfile snippets are taken over as they were entered.
+// JavaScript inputs following a request are appended to the
+// Script block of that request. JavaScript inputs preceding
+// the first request or following an execute statement are
+// prepended to the PreScript block of the next request.
+func export(entries []entry) string {
+	var (
+		parts   []string
+		pending []string
+		lastReq = -1
+	)
+
+	for _, e := range entries {
+		if e.script {
+			if lastReq >= 0 {
+				parts[lastReq] = appendToLastRequest(parts[lastReq], "Script", e.raw)
+			} else {
+				pending = append(pending, e.raw)
+			}
+			continue
+		}
+
+		actions := e.gf.Tests
+		if len(actions) == 0 {
+			parts = append(parts, e.raw)
+			continue
+		}
+
+		raw := e.raw
+		if len(pending) > 0 && actions[0].Type() == goatfile.ActionRequest {
+			raw = prependToFirstRequest(raw, "PreScript", strings.Join(pending, "\n"))
+			pending = nil
+		}
+		parts = append(parts, raw)
+
+		lastReq = -1
+		if actions[len(actions)-1].Type() == goatfile.ActionRequest {
+			lastReq = len(parts) - 1
+		}
+	}
+
+	if len(pending) > 0 {
+		var sb strings.Builder
+		sb.WriteString("// The following inputs could not be assigned to a request.\n")
+		for _, line := range strings.Split(strings.Join(pending, "\n"), "\n") {
+			sb.WriteString("// " + line + "\n")
+		}
+		parts = append(parts, strings.TrimSuffix(sb.String(), "\n"))
+	}
+
+	return strings.Join(parts, "\n\n"+requestDelimiter+"\n\n") + "\n"
+}
+
+// appendToLastRequest appends the given script to the end of
+// the block with the given name of the last request in raw.
+// If the block does not exist, it is created.
+func appendToLastRequest(raw, block, script string) string {
+	requests := splitRequests(raw)
+	last := len(requests) - 1
+	requests[last] = insertIntoBlock(requests[last], block, script, false)
+	return strings.Join(requests, "\n")
+}
+
+// prependToFirstRequest prepends the given script to the start
+// of the block with the given name of the first request in raw.
+// If the block does not exist, it is created.
+func prependToFirstRequest(raw, block, script string) string {
+	requests := splitRequests(raw)
+	requests[0] = insertIntoBlock(requests[0], block, script, true)
+	return strings.Join(requests, "\n")
+}
+
+// splitRequests splits raw at request delimiters which are
+// not part of delimited blocks. The delimiter lines are
+// kept at the start of the following part.
+func splitRequests(raw string) []string {
+	var (
+		parts   []string
+		current []string
+		inBlock bool
+	)
+
+	for _, line := range strings.Split(raw, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == blockDelimiter {
+			inBlock = !inBlock
+		}
+		if !inBlock && trimmed == requestDelimiter {
+			parts = append(parts, strings.Join(current, "\n"))
+			current = nil
+		}
+		current = append(current, line)
+	}
+
+	return append(parts, strings.Join(current, "\n"))
+}
+
+// insertIntoBlock inserts the given script into the block with
+// the given name in the given raw request, either at the start
+// or at the end of its content.
+func insertIntoBlock(raw, block, script string, atStart bool) string {
+	lines := strings.Split(strings.TrimRight(raw, "\n"), "\n")
+	header := fmt.Sprintf("[%s]", block)
+
+	start := -1
+	inBlock := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == blockDelimiter {
+			inBlock = !inBlock
+		}
+		if !inBlock && trimmed == header {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		lines = append(lines, "", header, blockDelimiter, script, blockDelimiter)
+		return strings.Join(lines, "\n")
+	}
+
+	delimited := start+1 < len(lines) && strings.TrimSpace(lines[start+1]) == blockDelimiter
+
+	var at int
+	switch {
+	case atStart && delimited:
+		at = start + 2
+	case atStart:
+		at = start + 1
+	case delimited:
+		at = len(lines)
+		for i := start + 2; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == blockDelimiter {
+				at = i
+				break
+			}
+		}
+	default:
+		at = len(lines)
+		for i := start + 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "" {
+				at = i
+				break
+			}
+		}
+	}
+
+	lines = append(lines[:at], append([]string{script}, lines[at:]...)...)
+	return strings.Join(lines, "\n")
+}
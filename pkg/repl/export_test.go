@@ -0,0 +1,81 @@
+package repl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+func snippet(t *testing.T, raw string) entry {
+	t.Helper()
+	gf, err := goatfile.Unmarshal(raw, "repl.goat")
+	require.Nil(t, err)
+	return entry{raw: raw, gf: gf}
+}
+
+func script(raw string) entry {
+	return entry{raw: raw, script: true}
+}
+
+func TestExport(t *testing.T) {
+	t.Run("scripts", func(t *testing.T) {
+		res := export([]entry{
+			script("id = 1"),
+			snippet(t, "GET https://example.com/{{.id}}"),
+			script("name = response.Body.name"),
+			script("info(name)"),
+			snippet(t, "POST https://example.com\n[Body]\n```\n{}\n```"),
+		})
+
+		assert.Equal(t,
+			"GET https://example.com/{{.id}}\n\n[PreScript]\n```\nid = 1\n```\n\n[Script]\n```\nname = response.Body.name\ninfo(name)\n```"+
+				"\n\n---\n\n"+
+				"POST https://example.com\n[Body]\n```\n{}\n```\n",
+			res)
+
+		gf, err := goatfile.Unmarshal(res, "export.goat")
+		require.Nil(t, err)
+		assert.Len(t, gf.Tests, 2)
+	})
+
+	t.Run("not-assignable", func(t *testing.T) {
+		res := export([]entry{
+			snippet(t, "execute other"),
+			script("a = 1\nb = 2"),
+		})
+
+		assert.Equal(t,
+			"execute other\n\n---\n\n"+
+				"// The following inputs could not be assigned to a request.\n// a = 1\n// b = 2\n",
+			res)
+	})
+}
+
+func TestInsertIntoBlock(t *testing.T) {
+	delimited := "GET https://example.com\n\n[Script]\n```\nfirst()\n```\n\n[Header]\nA: b\n"
+	undelimited := "GET https://example.com\n\n[Script]\nfirst()\n\n[Header]\nA: b"
+
+	assert.Equal(t,
+		"GET https://example.com\n\n[Script]\n```\nfirst()\nsecond()\n```\n\n[Header]\nA: b",
+		insertIntoBlock(delimited, "Script", "second()", false))
+	assert.Equal(t,
+		"GET https://example.com\n\n[Script]\n```\nsecond()\nfirst()\n```\n\n[Header]\nA: b",
+		insertIntoBlock(delimited, "Script", "second()", true))
+	assert.Equal(t,
+		"GET https://example.com\n\n[Script]\nfirst()\nsecond()\n\n[Header]\nA: b",
+		insertIntoBlock(undelimited, "Script", "second()", false))
+	assert.Equal(t,
+		"GET https://example.com\n\n[Script]\nsecond()\nfirst()\n\n[Header]\nA: b",
+		insertIntoBlock(undelimited, "Script", "second()", true))
+	assert.Equal(t,
+		"GET https://example.com\n\n[Script]\nfirst()\n\n[Header]\nA: b\n\n[PreScript]\n```\nsecond()\n```",
+		insertIntoBlock(undelimited, "PreScript", "second()", true))
+}
+
+func TestSplitRequests(t *testing.T) {
+	assert.Equal(t,
+		[]string{"GET a\n[Body]\n```\n---\n```", "---\nGET b"},
+		splitRequests("GET a\n[Body]\n```\n---\n```\n---\nGET b"))
+}
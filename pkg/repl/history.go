@@ -0,0 +1,94 @@
+package repl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+// maxHistoryEntries is the maximum number of
+// entries loaded from the history file.
+const maxHistoryEntries = 1000
+
+// History holds the inputs of the current and previous
+// sessions. Each input is appended to the history file,
+// if set, as JSON encoded string in a single line.
+type History struct {
+	file    string
+	entries []string
+}
+
+// LoadHistory loads the history from the given file. If the
+// file does not exist, an empty history is returned. If file
+// is empty, the history is not persisted.
+func LoadHistory(file string) (*History, error) {
+	t := &History{file: file}
+	if file == "" {
+		return t, nil
+	}
+
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, errs.WithPrefix("failed opening history file:", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var entry string
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		t.entries = append(t.entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errs.WithPrefix("failed reading history file:", err)
+	}
+
+	if len(t.entries) > maxHistoryEntries {
+		t.entries = t.entries[len(t.entries)-maxHistoryEntries:]
+	}
+
+	return t, nil
+}
+
+// Add appends the given entry to the history.
+func (t *History) Add(entry string) error {
+	t.entries = append(t.entries, entry)
+
+	if t.file == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.file), 0o700); err != nil {
+		return errs.WithPrefix("failed creating history directory:", err)
+	}
+
+	f, err := os.OpenFile(t.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errs.WithPrefix("failed opening history file:", err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Entries returns all entries of the history
+// from the oldest to the newest.
+func (t *History) Entries() []string {
+	return t.entries
+}
@@ -0,0 +1,46 @@
+package repl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "goat", "repl_history")
+
+	history, err := LoadHistory(file)
+	require.Nil(t, err)
+	assert.Empty(t, history.Entries())
+
+	require.Nil(t, history.Add("a = 1"))
+	require.Nil(t, history.Add("GET https://example.com\n[Header]\nA: b"))
+
+	history, err = LoadHistory(file)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"a = 1", "GET https://example.com\n[Header]\nA: b"}, history.Entries())
+
+	t.Run("in-memory", func(t *testing.T) {
+		history, err := LoadHistory("")
+		require.Nil(t, err)
+		require.Nil(t, history.Add("a = 1"))
+		assert.Equal(t, []string{"a = 1"}, history.Entries())
+	})
+
+	t.Run("max-entries", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "repl_history")
+		f, err := os.Create(file)
+		require.Nil(t, err)
+		for i := 0; i < maxHistoryEntries+10; i++ {
+			f.WriteString("\"entry\"\ninvalid\n")
+		}
+		f.Close()
+
+		history, err := LoadHistory(file)
+		require.Nil(t, err)
+		assert.Len(t, history.Entries(), maxHistoryEntries)
+	})
+}
@@ -0,0 +1,273 @@
+// Package repl provides an interactive read-eval-print
+// loop executing Goatfile snippets and JavaScript in a
+// persistent session.
+package repl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/clr"
+	"github.com/studio-b12/goat/pkg/executor"
+	"github.com/studio-b12/goat/pkg/goatfile"
+)
+
+const helpText = `Enter a Goatfile snippet or a line of JavaScript.
+
+Goatfile snippets start with a request method, 'use', 'execute'
+or a log section and end with an empty line outside of delimited
+blocks. The response of the last request is shown after execution
+and available as 'response' in the state.
+
+Commands:
+  .state           Show all state variables
+  .history         List the history
+  .run <n>         Execute the entry <n> of the history again
+  .export <file>   Write all executed inputs of the session to a Goatfile
+  .help            Show this help
+  .exit            Exit the REPL`
+
+var snippetStart = regexp.MustCompile(
+	`^((GET|HEAD|POST|PUT|PATCH|DELETE|OPTIONS|TRACE|CONNECT|GRPC)\s+\S|(use|execute)\s+\S|##)`)
+
+// Repl reads Goatfile snippets, JavaScript and commands
+// from an input and executes them in a Session.
+type Repl struct {
+	in      *bufio.Scanner
+	out     io.Writer
+	session *executor.Session
+	history *History
+	path    string
+
+	entries []entry
+}
+
+// New returns a new Repl reading from in and writing
+// to out. Inputs are executed in the given session and
+// added to the given history. Relative paths in Goatfile
+// snippets are resolved relative to dir.
+func New(in io.Reader, out io.Writer, session *executor.Session, history *History, dir string) *Repl {
+	var t Repl
+
+	t.in = bufio.NewScanner(in)
+	t.in.Buffer(nil, 1<<20)
+	t.out = out
+	t.session = session
+	t.history = history
+	t.path = filepath.Join(dir, "repl."+goatfile.FileExtension)
+
+	return &t
+}
+
+// Run reads and executes inputs until the input
+// is closed or '.exit' is entered.
+func (t *Repl) Run() {
+	t.printf("Goat REPL. Enter '.help' for more information.\n")
+
+	for {
+		input, ok := t.read()
+		if !ok {
+			t.printf("\n")
+			return
+		}
+
+		if input == "" {
+			continue
+		}
+
+		if strings.HasPrefix(input, ".") {
+			if !t.command(input) {
+				return
+			}
+			continue
+		}
+
+		if err := t.history.Add(input); err != nil {
+			t.printError(err)
+		}
+		t.execute(input)
+	}
+}
+
+// read reads the next input. Goatfile snippets are read
+// until an empty line outside of delimited blocks.
+func (t *Repl) read() (string, bool) {
+	t.printf("%s ", clr.Print(clr.Format("goat>", clr.ColorFGCyan)))
+	if !t.in.Scan() {
+		return "", false
+	}
+
+	first := strings.TrimSpace(t.in.Text())
+	if !isSnippet(first) {
+		return first, true
+	}
+
+	lines := []string{first}
+	inBlock := false
+	for {
+		t.printf("%s ", clr.Print(clr.Format("  ...", clr.ColorFGBlack)))
+		if !t.in.Scan() {
+			break
+		}
+		line := t.in.Text()
+		if strings.TrimSpace(line) == blockDelimiter {
+			inBlock = !inBlock
+		}
+		if !inBlock && strings.TrimSpace(line) == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), true
+}
+
+// command executes the given REPL command. It
+// returns false if the REPL shall be exited.
+func (t *Repl) command(input string) bool {
+	cmd, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch cmd {
+	case ".exit":
+		return false
+	case ".help":
+		t.printf("%s\n", helpText)
+	case ".state":
+		t.printValue(t.session.State())
+	case ".history":
+		for i, e := range t.history.Entries() {
+			t.printf("%s %s\n",
+				clr.Print(clr.Format(fmt.Sprintf("%4d", i+1), clr.ColorFGBlack)),
+				strings.ReplaceAll(e, "\n", "\n     "))
+		}
+	case ".run":
+		entries := t.history.Entries()
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(entries) {
+			t.printf("usage: .run <n> where n is between 1 and %d\n", len(entries))
+			return true
+		}
+		input := entries[n-1]
+		t.printf("%s\n", input)
+		if err = t.history.Add(input); err != nil {
+			t.printError(err)
+		}
+		t.execute(input)
+	case ".export":
+		if arg == "" {
+			t.printf("usage: .export <file>\n")
+			return true
+		}
+		file := goatfile.Extend(arg, goatfile.FileExtension)
+		if err := os.WriteFile(file, []byte(export(t.entries)), 0o644); err != nil {
+			t.printError(err)
+			return true
+		}
+		t.printf("Session has been exported to %s\n", file)
+	default:
+		t.printf("unknown command %q, enter '.help' to list all commands\n", cmd)
+	}
+
+	return true
+}
+
+// execute executes the given input either as Goatfile
+// snippet or as JavaScript.
+func (t *Repl) execute(input string) {
+	if !isSnippet(input) {
+		v, err := t.session.Eval(input)
+		if err != nil {
+			t.printError(err)
+			return
+		}
+		t.entries = append(t.entries, entry{raw: input, script: true})
+		if v != nil {
+			t.printValue(v)
+		}
+		return
+	}
+
+	gf, err := goatfile.Unmarshal(input, t.path)
+	if err != nil {
+		t.printError(err)
+		return
+	}
+
+	_, err = t.session.Execute(gf)
+	t.entries = append(t.entries, entry{raw: input, gf: gf})
+	if err != nil {
+		t.printError(err)
+		return
+	}
+
+	if !hasRequest(gf) {
+		return
+	}
+	if resp, ok := t.session.State()["response"].(executor.Response); ok {
+		t.printResponse(resp)
+	}
+}
+
+func hasRequest(gf goatfile.Goatfile) bool {
+	for _, act := range gf.Tests {
+		if act.Type() == goatfile.ActionRequest {
+			return true
+		}
+	}
+	return false
+}
+
+func isSnippet(input string) bool {
+	return snippetStart.MatchString(input)
+}
+
+func (t *Repl) printResponse(resp executor.Response) {
+	t.printf("%s %s\n", resp.Proto, clr.Print(clr.Format(resp.Status, statusColor(resp.StatusCode))))
+	if len(resp.BodyRaw) == 0 {
+		return
+	}
+	switch body := resp.Body.(type) {
+	case executor.RawData, nil:
+		t.printf("%s\n", strings.TrimSuffix(resp.BodyRaw.String(), "\n"))
+	case string:
+		t.printf("%s\n", strings.TrimSuffix(body, "\n"))
+	default:
+		t.printValue(body)
+	}
+}
+
+func (t *Repl) printValue(v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.printf("%v\n", v)
+		return
+	}
+	t.printf("%s\n", data)
+}
+
+func (t *Repl) printError(err error) {
+	t.printf("%s %s\n", clr.Print(clr.Format("error:", clr.ColorFGRed, clr.FormatBold)), err.Error())
+}
+
+func (t *Repl) printf(format string, v ...any) {
+	fmt.Fprintf(t.out, format, v...)
+}
+
+func statusColor(code int) clr.FormatCode {
+	switch {
+	case code >= 500:
+		return clr.ColorFGRed
+	case code >= 400:
+		return clr.ColorFGBrown
+	default:
+		return clr.ColorFGGreen
+	}
+}
@@ -0,0 +1,78 @@
+package repl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/studio-b12/goat/pkg/engine"
+	"github.com/studio-b12/goat/pkg/executor"
+	"github.com/studio-b12/goat/pkg/requester"
+)
+
+func TestRepl(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		case "/me":
+			c, err := r.Cookie("session")
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"session": "` + c.Value + `", "id": ` + r.URL.Query().Get("id") + `}`))
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+
+	input := strings.Join([]string{
+		"id = 42",
+		"POST {{.instance}}/login",
+		"",
+		"GET {{.instance}}/me?id={{.id}}",
+		"[Header]",
+		"Accept: application/json",
+		"",
+		"response.Body.session",
+		"undefinedFunction()",
+		".unknown",
+		".run 1",
+		".export " + filepath.Join(dir, "session"),
+		".exit",
+		"id",
+	}, "\n")
+
+	exec := executor.New(context.Background(), engine.NewGoja, requester.NewHttpWithCookies(func(*http.Client) {}))
+	session := exec.NewSession(engine.State{"instance": srv.URL})
+	history, err := LoadHistory("")
+	require.Nil(t, err)
+
+	var out strings.Builder
+	New(strings.NewReader(input), &out, session, history, dir).Run()
+
+	assert.Contains(t, out.String(), "200 OK")
+	assert.Contains(t, out.String(), `"session": "abc"`)
+	assert.Contains(t, out.String(), "\"abc\"\n")
+	assert.Contains(t, out.String(), "ReferenceError: undefinedFunction is not defined")
+	assert.Contains(t, out.String(), `unknown command ".unknown"`)
+
+	assert.Len(t, history.Entries(), 6)
+	assert.Equal(t, int64(42), session.State()["id"])
+
+	exported, err := os.ReadFile(filepath.Join(dir, "session.goat"))
+	require.Nil(t, err)
+	assert.Equal(t,
+		"POST {{.instance}}/login\n\n[PreScript]\n```\nid = 42\n```\n\n---\n\n"+
+			"GET {{.instance}}/me?id={{.id}}\n[Header]\nAccept: application/json\n\n[Script]\n```\nresponse.Body.session\nid = 42\n```\n",
+		string(exported))
+}
@@ -1,9 +1,11 @@
 package requester
 
 import (
+	"fmt"
+	"io"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
+	"sync"
 
 	"github.com/zekrotja/rogu/log"
 )
@@ -33,10 +35,18 @@ func (t noGetWrapper) Cookies(u *url.URL) []*http.Cookie { return nil }
 // of cookie handling.
 type HttpWithCookies struct {
 	client     *http.Client
-	cookieJars map[any]http.CookieJar
+	cookieJars map[string]*Jar
+	defaults   TransportOptions
+
+	mtx        *sync.Mutex
+	transports map[string]http.RoundTripper
 }
 
-var _ Requester = (*HttpWithCookies)(nil)
+var (
+	_ Requester  = (*HttpWithCookies)(nil)
+	_ CookieJars = (*HttpWithCookies)(nil)
+	_ Sessions   = (*HttpWithCookies)(nil)
+)
 
 // NewHttpWithCookies returns a new instance of HttpWithCookies.
 // cfg is getting passed the instance of http.Client which you
@@ -48,11 +58,28 @@ func NewHttpWithCookies(cfg func(client *http.Client)) *HttpWithCookies {
 
 	cfg(t.client)
 
-	t.cookieJars = make(map[any]http.CookieJar)
+	t.cookieJars = make(map[string]*Jar)
+	t.mtx = &sync.Mutex{}
+	t.transports = make(map[string]http.RoundTripper)
 
 	return &t
 }
 
+// NewSession returns a new HttpWithCookies sharing the client,
+// the transports and the default transport options with t but
+// using its own set of cookie jars.
+func (t HttpWithCookies) NewSession() Requester {
+	t.cookieJars = make(map[string]*Jar)
+	return &t
+}
+
+// SetDefaultTransportOptions sets the transport options used
+// for all requests. Options specified for a single request
+// take precedence over the default options.
+func (t *HttpWithCookies) SetDefaultTransportOptions(opt TransportOptions) {
+	t.defaults = opt
+}
+
 func (t HttpWithCookies) Do(req *http.Request, opt Options) (*http.Response, error) {
 	jar, err := t.getJar(&opt)
 	if err != nil {
@@ -66,13 +93,35 @@ func (t HttpWithCookies) Do(req *http.Request, opt Options) (*http.Response, err
 		"cookies", jar.Cookies(req.URL),
 	).Msg("Sending request ...")
 
+	transport, err := t.getTransport(&opt)
+	if err != nil {
+		return nil, err
+	}
+
 	client := *t.client
 	client.Jar = jar
+	client.Transport = transport
 
 	if !opt.FollowRedirects {
 		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		}
+	} else {
+		checkRedirect := client.CheckRedirect
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			logger.Trace().Fields(
+				"statusCode", req.Response.StatusCode,
+				"from", via[len(via)-1].URL,
+				"to", req.URL,
+			).Msg("Following redirect ...")
+			if checkRedirect != nil {
+				return checkRedirect(req, via)
+			}
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		}
 	}
 
 	res, err := client.Do(req)
@@ -96,26 +145,112 @@ func (t HttpWithCookies) Do(req *http.Request, opt Options) (*http.Response, err
 // and/or noGetWrapper depending on the passed
 // options.
 func (t HttpWithCookies) getJar(opt *Options) (jar http.CookieJar, err error) {
-	key := opt.CookieJar
+	jar = t.CookieJar(opt.CookieJar)
+
+	if !opt.SendCookies {
+		jar = noGetWrapper{jar}
+	}
+	if !opt.StoreCookies {
+		jar = noSetWrapper{jar}
+	}
+
+	return jar, nil
+}
+
+// CookieJar returns the cookie jar with the given key. If no
+// jar exists with the given key, a new jar is created. If key
+// is nil, the 'default' jar is returned.
+//
+// Jars are identified by the string representation of their
+// key, so the keys 1 and "1" refer to the same jar.
+func (t HttpWithCookies) CookieJar(key any) *Jar {
 	if key == nil {
 		key = "default"
 	}
+	name := fmt.Sprint(key)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
 
-	jar, ok := t.cookieJars[key]
+	jar, ok := t.cookieJars[name]
 	if !ok {
-		jar, err = cookiejar.New(nil)
-		if err != nil {
-			return nil, err
+		jar = NewJar()
+		t.cookieJars[name] = jar
+	}
+
+	return jar
+}
+
+// SaveCookies writes all cookie jars to w.
+func (t HttpWithCookies) SaveCookies(w io.Writer) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return WriteJars(w, t.cookieJars)
+}
+
+// LoadCookies reads cookie jars written by SaveCookies from r.
+// Loaded cookies are added to the jars with the same name.
+func (t HttpWithCookies) LoadCookies(r io.Reader) error {
+	jars, err := LoadJars(r)
+	if err != nil {
+		return err
+	}
+
+	for name, loaded := range jars {
+		jar := t.CookieJar(name)
+		for _, entry := range loaded.entries {
+			u, err := url.Parse(entry.URL)
+			if err != nil {
+				return err
+			}
+			jar.SetCookies(u, []*http.Cookie{entry.Cookie})
 		}
-		t.cookieJars[key] = jar
 	}
 
-	if !opt.SendCookies {
-		jar = noGetWrapper{jar}
+	return nil
+}
+
+// getTransport returns the transport used for a request with
+// the given options. If transport options are specified, a
+// transport is created for each distinct configuration, based
+// on the transport of the client, and re-used for subsequent
+// requests.
+func (t HttpWithCookies) getTransport(opt *Options) (http.RoundTripper, error) {
+	transportOpt := opt.TransportOptions.Merge(t.defaults)
+	if transportOpt.IsEmpty() {
+		return t.client.Transport, nil
 	}
-	if !opt.StoreCookies {
-		jar = noSetWrapper{jar}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	key := transportOpt.key()
+	if transport, ok := t.transports[key]; ok {
+		return transport, nil
 	}
 
-	return jar, nil
+	base, ok := t.client.Transport.(*http.Transport)
+	if !ok || base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport := base.Clone()
+	err := transportOpt.Apply(transport)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().Fields(
+		"tlscert", transportOpt.TLS.CertFile,
+		"cacert", transportOpt.TLS.CAFiles,
+		"minversion", transportOpt.TLS.MinVersion,
+		"servername", transportOpt.TLS.ServerName,
+		"proxy", transportOpt.Proxy,
+		"unixsocket", transportOpt.UnixSocket,
+		"resolve", transportOpt.Resolve,
+	).Msg("Created transport")
+
+	t.transports[key] = transport
+	return transport, nil
 }
@@ -0,0 +1,246 @@
+package requester
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Jar implements http.CookieJar using the cookiejar of the
+// standard library. Additionally, all stored cookies are
+// recorded with their attributes so that they can be listed
+// and persisted.
+type Jar struct {
+	mtx     sync.Mutex
+	jar     *cookiejar.Jar
+	entries map[string]jarEntry
+}
+
+var _ http.CookieJar = (*Jar)(nil)
+
+// jarEntry is a cookie stored in a Jar together
+// with the URL of the response which set it.
+type jarEntry struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// NewJar returns a new empty Jar.
+func NewJar() *Jar {
+	var t Jar
+	t.jar, _ = cookiejar.New(nil)
+	t.entries = make(map[string]jarEntry)
+	return &t
+}
+
+func (t *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.jar.SetCookies(u, cookies)
+
+	for _, c := range cookies {
+		key := entryKey(u, c)
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(time.Now())) {
+			delete(t.entries, key)
+			continue
+		}
+		if !t.stored(u, c) {
+			continue
+		}
+		cookie := *c
+		if cookie.MaxAge > 0 {
+			cookie.Expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+			cookie.MaxAge = 0
+		}
+		t.entries[key] = jarEntry{URL: u.String(), Cookie: &cookie}
+	}
+}
+
+func (t *Jar) Cookies(u *url.URL) []*http.Cookie {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.jar.Cookies(u)
+}
+
+// All returns all unexpired cookies stored in the jar
+// including their attributes sorted by domain, path
+// and name. Domain and Path are set to their effective
+// values if they have not been specified by the server.
+func (t *Jar) All() []*http.Cookie {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.removeExpired()
+
+	keys := make([]string, 0, len(t.entries))
+	for key := range t.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	cookies := make([]*http.Cookie, 0, len(keys))
+	for _, key := range keys {
+		c := *t.entries[key].Cookie
+		if u, err := url.Parse(t.entries[key].URL); err == nil {
+			if c.Domain == "" {
+				c.Domain = u.Hostname()
+			}
+			if c.Path == "" || c.Path[0] != '/' {
+				c.Path = defaultCookiePath(u.Path)
+			}
+		}
+		cookies = append(cookies, &c)
+	}
+
+	return cookies
+}
+
+// Clear removes all cookies from the jar.
+func (t *Jar) Clear() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.jar, _ = cookiejar.New(nil)
+	t.entries = make(map[string]jarEntry)
+}
+
+// MarshalJSON encodes all unexpired cookies of the jar.
+func (t *Jar) MarshalJSON() ([]byte, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.removeExpired()
+
+	entries := make([]jarEntry, 0, len(t.entries))
+	for _, entry := range t.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entryKeyOf(entries[i]) < entryKeyOf(entries[j])
+	})
+
+	return json.Marshal(entries)
+}
+
+// UnmarshalJSON decodes cookies encoded by MarshalJSON
+// and stores them in the jar.
+func (t *Jar) UnmarshalJSON(data []byte) error {
+	var entries []jarEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	if t.jar == nil {
+		t.jar, _ = cookiejar.New(nil)
+		t.entries = make(map[string]jarEntry)
+	}
+
+	for _, entry := range entries {
+		u, err := url.Parse(entry.URL)
+		if err != nil {
+			return err
+		}
+		if entry.Cookie == nil {
+			continue
+		}
+		t.SetCookies(u, []*http.Cookie{entry.Cookie})
+	}
+
+	return nil
+}
+
+// stored returns true if the given cookie set for u has
+// been accepted by the underlying jar. Cookies are rejected,
+// for example, if their domain does not match the host of u.
+func (t *Jar) stored(u *url.URL, c *http.Cookie) bool {
+	domain := strings.TrimPrefix(c.Domain, ".")
+	if domain == "" {
+		domain = u.Hostname()
+	}
+	if port := u.Port(); port != "" {
+		domain += ":" + port
+	}
+
+	p := c.Path
+	if p == "" || p[0] != '/' {
+		p = defaultCookiePath(u.Path)
+	}
+
+	// The scheme is always https so that secure cookies
+	// are returned as well.
+	for _, stored := range t.jar.Cookies(&url.URL{Scheme: "https", Host: domain, Path: p}) {
+		if stored.Name == c.Name && stored.Value == c.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Jar) removeExpired() {
+	now := time.Now()
+	for key, entry := range t.entries {
+		if !entry.Cookie.Expires.IsZero() && entry.Cookie.Expires.Before(now) {
+			delete(t.entries, key)
+		}
+	}
+}
+
+func entryKeyOf(entry jarEntry) string {
+	u, _ := url.Parse(entry.URL)
+	if u == nil {
+		u = &url.URL{}
+	}
+	return entryKey(u, entry.Cookie)
+}
+
+// entryKey returns the key identifying a cookie by its domain,
+// path and name as specified in RFC 6265, section 5.3.
+func entryKey(u *url.URL, c *http.Cookie) string {
+	domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+	if domain == "" {
+		domain = u.Hostname()
+	}
+
+	p := c.Path
+	if p == "" || p[0] != '/' {
+		p = defaultCookiePath(u.Path)
+	}
+
+	return fmt.Sprintf("%s;%s;%s", domain, p, c.Name)
+}
+
+func defaultCookiePath(p string) string {
+	if p == "" || p[0] != '/' {
+		return "/"
+	}
+	return path.Dir(p)
+}
+
+// LoadJars decodes cookie jars previously written by
+// WriteJars from r and returns them by their names.
+func LoadJars(r io.Reader) (map[string]*Jar, error) {
+	jars := make(map[string]*Jar)
+	err := json.NewDecoder(r).Decode(&jars)
+	if err == io.EOF {
+		err = nil
+	}
+	return jars, err
+}
+
+// WriteJars encodes the given cookie jars by their
+// names to w.
+func WriteJars(w io.Writer, jars map[string]*Jar) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jars)
+}
@@ -0,0 +1,90 @@
+package requester
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJar(t *testing.T) {
+	u, _ := url.Parse("https://example.com/auth/login")
+
+	jar := NewJar()
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "abc", Path: "/", HttpOnly: true},
+		{Name: "pref", Value: "dark"},
+		{Name: "expired", Value: "x", Expires: time.Now().Add(-time.Hour)},
+	})
+
+	all := jar.All()
+	require.Len(t, all, 2)
+	assert.Equal(t, "session", all[0].Name)
+	assert.Equal(t, "example.com", all[0].Domain)
+	assert.Equal(t, "pref", all[1].Name)
+	assert.Equal(t, "/auth", all[1].Path)
+
+	assert.Len(t, jar.Cookies(u), 2)
+	assert.Len(t, jar.Cookies(&url.URL{Scheme: "https", Host: "example.com", Path: "/"}), 1)
+
+	// Cookies rejected by the jar are not recorded.
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "foreign", Value: "x", Domain: "other.com"},
+		{Name: "sub", Value: "x", Domain: "api.example.com"},
+	})
+	assert.Len(t, jar.All(), 2)
+
+	// Deleting a cookie via Max-Age < 0.
+	jar.SetCookies(u, []*http.Cookie{{Name: "pref", MaxAge: -1}})
+	assert.Len(t, jar.All(), 1)
+
+	jar.Clear()
+	assert.Empty(t, jar.All())
+	assert.Empty(t, jar.Cookies(u))
+}
+
+func TestHttpWithCookies_Persistence(t *testing.T) {
+	u, _ := url.Parse("https://example.com/")
+
+	client := NewHttpWithCookies(func(*http.Client) {})
+	client.CookieJar("admin").SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "abc", MaxAge: 3600},
+	})
+	client.CookieJar(1).SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "def"},
+	})
+
+	var buf bytes.Buffer
+	require.Nil(t, client.SaveCookies(&buf))
+
+	loaded := NewHttpWithCookies(func(*http.Client) {})
+	require.Nil(t, loaded.LoadCookies(&buf))
+
+	cookies := loaded.CookieJar("admin").Cookies(u)
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "abc", cookies[0].Value)
+
+	cookies = loaded.CookieJar(int64(1)).Cookies(u)
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "def", cookies[0].Value)
+
+	assert.Empty(t, loaded.CookieJar("default").All())
+}
+
+func TestHttpWithCookies_NewSession(t *testing.T) {
+	u, _ := url.Parse("https://example.com/")
+
+	client := NewHttpWithCookies(func(*http.Client) {})
+	client.CookieJar(nil).SetCookies(u, []*http.Cookie{{Name: "session", Value: "abc"}})
+
+	session := client.NewSession().(*HttpWithCookies)
+	assert.Empty(t, session.CookieJar(nil).Cookies(u))
+
+	session.CookieJar(nil).SetCookies(u, []*http.Cookie{{Name: "session", Value: "def"}})
+	assert.Equal(t, "abc", client.CookieJar(nil).Cookies(u)[0].Value)
+	assert.Equal(t, "def", session.CookieJar(nil).Cookies(u)[0].Value)
+}
@@ -1,5 +1,7 @@
 package requester
 
+import "github.com/studio-b12/goat/pkg/util"
+
 // Options wraps request specific options.
 type Options struct {
 	CookieJar       any
@@ -7,6 +9,7 @@ type Options struct {
 	SendCookies     bool
 	ResponseType    string
 	FollowRedirects bool
+	TransportOptions
 }
 
 // OptionsFromMap takes a map and builds an
@@ -37,5 +40,14 @@ func OptionsFromMap(m map[string]any) Options {
 		opt.FollowRedirects = v
 	}
 
+	opt.TLS = TLSOptionsFromMap(m)
+	if v, ok := m["proxy"].(string); ok {
+		opt.Proxy = v
+	}
+	if v, ok := m["unixsocket"].(string); ok {
+		opt.UnixSocket = v
+	}
+	opt.Resolve = util.StringList(m["resolve"])
+
 	return opt
 }
@@ -0,0 +1,26 @@
+package requester
+
+import "net/http"
+
+// maxRedirects is the maximum number of redirects
+// followed for a single request.
+const maxRedirects = 10
+
+// Redirects returns the chain of redirect responses which
+// have been followed to receive the given response, in the
+// order they have been received.
+//
+// The bodies of the returned responses have already been
+// closed, but status, header and request are retained.
+func Redirects(res *http.Response) []*http.Response {
+	var hops []*http.Response
+	for req := res.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		hops = append(hops, req.Response)
+	}
+
+	for i, j := 0, len(hops)-1; i < j; i, j = i+1, j-1 {
+		hops[i], hops[j] = hops[j], hops[i]
+	}
+
+	return hops
+}
@@ -9,3 +9,20 @@ type Requester interface {
 	// returns the response.
 	Do(req *http.Request, opt Options) (*http.Response, error)
 }
+
+// CookieJars provides access to the named
+// cookie jars used by a Requester.
+type CookieJars interface {
+	// CookieJar returns the cookie jar with
+	// the given key.
+	CookieJar(key any) *Jar
+}
+
+// Sessions is implemented by Requesters which can
+// create new sessions with their own set of cookie
+// jars.
+type Sessions interface {
+	// NewSession returns a new Requester using
+	// its own set of cookie jars.
+	NewSession() Requester
+}
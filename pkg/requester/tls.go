@@ -0,0 +1,160 @@
+package requester
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/errs"
+	"github.com/studio-b12/goat/pkg/util"
+)
+
+var (
+	ErrInvalidTLSVersion = errors.New("invalid TLS version, must be one of 1.0, 1.1, 1.2 or 1.3")
+	ErrMissingClientKey  = errors.New("a client certificate must be specified together with a key")
+	ErrInvalidCAFile     = errors.New("no valid certificates found in CA file")
+)
+
+// TLSOptions wraps the TLS configuration
+// used for a request.
+type TLSOptions struct {
+	// CertFile and KeyFile specify the paths to a
+	// PEM encoded client certificate and key used
+	// for mutual TLS.
+	CertFile string
+	KeyFile  string
+	// CAFiles contains paths to PEM encoded root CA
+	// certificates used to verify server certificates
+	// instead of the system's root CAs.
+	CAFiles []string
+	// MinVersion is the minimum accepted TLS version
+	// (1.0, 1.1, 1.2 or 1.3).
+	MinVersion string
+	// ServerName overrides the server name used for
+	// SNI and certificate verification.
+	ServerName string
+}
+
+// TLSOptionsFromMap takes a map and builds a
+// TLSOptions instance from matching key-value
+// pairs.
+func TLSOptionsFromMap(m map[string]any) TLSOptions {
+	var opt TLSOptions
+
+	if v, ok := m["tlscert"].(string); ok {
+		opt.CertFile = v
+	}
+	if v, ok := m["tlskey"].(string); ok {
+		opt.KeyFile = v
+	}
+	opt.CAFiles = util.StringList(m["cacert"])
+	if v, ok := m["tlsminversion"].(string); ok {
+		opt.MinVersion = v
+	}
+	if v, ok := m["servername"].(string); ok {
+		opt.ServerName = v
+	}
+
+	return opt
+}
+
+// IsEmpty returns true if no TLS options are set.
+func (t TLSOptions) IsEmpty() bool {
+	return t.CertFile == "" && t.KeyFile == "" && len(t.CAFiles) == 0 &&
+		t.MinVersion == "" && t.ServerName == ""
+}
+
+// Merge returns a copy of t where all unset
+// values are taken from def.
+func (t TLSOptions) Merge(def TLSOptions) TLSOptions {
+	if t.CertFile == "" && t.KeyFile == "" {
+		t.CertFile = def.CertFile
+		t.KeyFile = def.KeyFile
+	}
+	if len(t.CAFiles) == 0 {
+		t.CAFiles = def.CAFiles
+	}
+	if t.MinVersion == "" {
+		t.MinVersion = def.MinVersion
+	}
+	if t.ServerName == "" {
+		t.ServerName = def.ServerName
+	}
+	return t
+}
+
+// Config returns a clone of base with the TLS options
+// applied. If base is nil, a new tls.Config is created.
+func (t TLSOptions) Config(base *tls.Config) (*tls.Config, error) {
+	cfg := &tls.Config{}
+	if base != nil {
+		cfg = base.Clone()
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		if t.CertFile == "" || t.KeyFile == "" {
+			return nil, ErrMissingClientKey
+		}
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, errs.WithPrefix("failed loading client certificate:", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(t.CAFiles) > 0 {
+		pool := x509.NewCertPool()
+		for _, caFile := range t.CAFiles {
+			data, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, errs.WithPrefix("failed reading CA file:", err)
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, errs.WithSuffix(ErrInvalidCAFile, fmt.Sprintf("(%s)", caFile))
+			}
+		}
+		cfg.RootCAs = pool
+	}
+
+	if t.MinVersion != "" {
+		version, err := parseTLSVersion(t.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		cfg.MinVersion = version
+	}
+
+	if t.ServerName != "" {
+		cfg.ServerName = t.ServerName
+	}
+
+	return cfg, nil
+}
+
+func (t TLSOptions) key() string {
+	return strings.Join([]string{
+		t.CertFile,
+		t.KeyFile,
+		strings.Join(t.CAFiles, ","),
+		t.MinVersion,
+		t.ServerName,
+	}, "|")
+}
+
+func parseTLSVersion(v string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(v), "tls") {
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, errs.WithSuffix(ErrInvalidTLSVersion, fmt.Sprintf("(%s)", v))
+	}
+}
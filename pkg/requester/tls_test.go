@@ -0,0 +1,179 @@
+package requester
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpWithCookies_TLS(t *testing.T) {
+	dir := t.TempDir()
+
+	caCert, caKey := generateCert(t, dir, "ca", nil, nil)
+	serverCert, serverKey := generateCert(t, dir, "server", caCert, caKey)
+	generateCert(t, dir, "client", caCert, caKey)
+
+	serverPair := tls.Certificate{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS13,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	url := "https://127.0.0.1:" + port
+
+	client := NewHttpWithCookies(func(client *http.Client) {
+		client.Transport = &http.Transport{}
+	})
+
+	t.Run("no-client-cert", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		_, err := client.Do(req, Options{TransportOptions: TransportOptions{TLS: TLSOptions{
+			CAFiles:    []string{filepath.Join(dir, "ca.pem")},
+			ServerName: "server",
+		}}})
+		assert.Error(t, err)
+	})
+
+	t.Run("mtls", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		res, err := client.Do(req, Options{TransportOptions: TransportOptions{TLS: TLSOptions{
+			CertFile:   filepath.Join(dir, "client.pem"),
+			KeyFile:    filepath.Join(dir, "client-key.pem"),
+			CAFiles:    []string{filepath.Join(dir, "ca.pem")},
+			ServerName: "server",
+			MinVersion: "1.3",
+		}}})
+		require.Nil(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, uint16(tls.VersionTLS13), res.TLS.Version)
+		assert.Len(t, client.transports, 2)
+	})
+
+	t.Run("default-options", func(t *testing.T) {
+		client := NewHttpWithCookies(func(client *http.Client) {
+			client.Transport = &http.Transport{}
+		})
+		client.SetDefaultTransportOptions(TransportOptions{TLS: TLSOptions{
+			CertFile:   filepath.Join(dir, "client.pem"),
+			KeyFile:    filepath.Join(dir, "client-key.pem"),
+			CAFiles:    []string{filepath.Join(dir, "ca.pem")},
+			ServerName: "server",
+		}})
+
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		res, err := client.Do(req, Options{})
+		require.Nil(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		// Per-request options take precedence.
+		req, _ = http.NewRequest(http.MethodGet, url, nil)
+		_, err = client.Do(req, Options{TransportOptions: TransportOptions{TLS: TLSOptions{ServerName: "foo"}}})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		_, err := client.Do(req, Options{TransportOptions: TransportOptions{TLS: TLSOptions{MinVersion: "2.0"}}})
+		assert.ErrorIs(t, err, ErrInvalidTLSVersion)
+
+		_, err = client.Do(req, Options{TransportOptions: TransportOptions{TLS: TLSOptions{CertFile: "client.pem"}}})
+		assert.ErrorIs(t, err, ErrMissingClientKey)
+	})
+}
+
+func TestTLSOptionsFromMap(t *testing.T) {
+	opt := TLSOptionsFromMap(map[string]any{
+		"tlscert":       "client.pem",
+		"tlskey":        "client-key.pem",
+		"cacert":        []any{"a.pem", "b.pem"},
+		"tlsminversion": "1.2",
+		"servername":    "example.com",
+	})
+	assert.Equal(t, TLSOptions{
+		CertFile:   "client.pem",
+		KeyFile:    "client-key.pem",
+		CAFiles:    []string{"a.pem", "b.pem"},
+		MinVersion: "1.2",
+		ServerName: "example.com",
+	}, opt)
+
+	opt = TLSOptionsFromMap(map[string]any{"cacert": "a.pem"})
+	assert.Equal(t, []string{"a.pem"}, opt.CAFiles)
+}
+
+// generateCert creates a certificate with the given common name
+// signed by parent and writes the certificate and key as PEM files
+// to dir. If parent is nil, a self-signed CA certificate is created.
+func generateCert(
+	t *testing.T,
+	dir, name string,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		parent = tmpl
+		parentKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+
+	require.Nil(t, os.WriteFile(filepath.Join(dir, name+".pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, name+"-key.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	return cert, key
+}
@@ -0,0 +1,154 @@
+package requester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+var (
+	ErrInvalidProxyURL  = errors.New("invalid proxy URL, scheme must be one of http, https, socks5 or socks5h")
+	ErrInvalidResolve   = errors.New("invalid resolve entry, must be in format 'host:port:addr'")
+	ErrInvalidSocketURL = errors.New("invalid unix socket, must be a path or in format 'unix:///path/to/socket'")
+)
+
+// TransportOptions wraps options which require
+// a distinct transport to perform a request.
+type TransportOptions struct {
+	TLS TLSOptions
+	// Proxy is the URL of a HTTP or SOCKS5 proxy
+	// used to perform requests.
+	Proxy string
+	// UnixSocket is the path to a unix domain socket
+	// used to connect to the server, optionally in the
+	// format 'unix:///path/to/socket'.
+	UnixSocket string
+	// Resolve contains curl-style DNS overrides in the
+	// format 'host:port:addr'. Connections to host:port
+	// are dialed to addr:port instead.
+	Resolve []string
+}
+
+// IsEmpty returns true if no transport options are set.
+func (t TransportOptions) IsEmpty() bool {
+	return t.TLS.IsEmpty() && t.Proxy == "" && t.UnixSocket == "" && len(t.Resolve) == 0
+}
+
+// Merge returns a copy of t where all unset
+// values are taken from def.
+func (t TransportOptions) Merge(def TransportOptions) TransportOptions {
+	t.TLS = t.TLS.Merge(def.TLS)
+	if t.Proxy == "" {
+		t.Proxy = def.Proxy
+	}
+	if t.UnixSocket == "" {
+		t.UnixSocket = def.UnixSocket
+	}
+	if len(t.Resolve) == 0 {
+		t.Resolve = def.Resolve
+	}
+	return t
+}
+
+// Apply sets the transport options to the given transport.
+func (t TransportOptions) Apply(transport *http.Transport) error {
+	tlsConfig, err := t.TLS.Config(transport.TLSClientConfig)
+	if err != nil {
+		return err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if t.Proxy != "" {
+		proxyURL, err := url.Parse(t.Proxy)
+		if err != nil {
+			return errs.WithPrefix("failed parsing proxy URL:", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return errs.WithSuffix(ErrInvalidProxyURL, fmt.Sprintf("(%s)", t.Proxy))
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if t.UnixSocket == "" && len(t.Resolve) == 0 {
+		return nil
+	}
+
+	socket, err := parseUnixSocket(t.UnixSocket)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := parseResolve(t.Resolve)
+	if err != nil {
+		return err
+	}
+
+	dialContext := transport.DialContext
+	if dialContext == nil {
+		dialContext = (&net.Dialer{}).DialContext
+	}
+
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if socket != "" {
+			return dialContext(ctx, "unix", socket)
+		}
+		if override, ok := overrides[addr]; ok {
+			addr = override
+		}
+		return dialContext(ctx, network, addr)
+	}
+
+	return nil
+}
+
+func (t TransportOptions) key() string {
+	return strings.Join([]string{
+		t.TLS.key(),
+		t.Proxy,
+		t.UnixSocket,
+		strings.Join(t.Resolve, ","),
+	}, "|")
+}
+
+// parseUnixSocket returns the path of the given unix
+// socket which is either a path or an URL with the
+// scheme 'unix'.
+func parseUnixSocket(v string) (string, error) {
+	if !strings.Contains(v, "://") {
+		return v, nil
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || u.Scheme != "unix" || u.Path == "" {
+		return "", errs.WithSuffix(ErrInvalidSocketURL, fmt.Sprintf("(%s)", v))
+	}
+
+	return u.Path, nil
+}
+
+// parseResolve parses the given list of resolve entries in the
+// format 'host:port:addr' to a map of 'host:port' to 'addr:port'.
+func parseResolve(entries []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		host, rest, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errs.WithSuffix(ErrInvalidResolve, fmt.Sprintf("(%s)", entry))
+		}
+		port, addr, ok := strings.Cut(rest, ":")
+		if !ok || host == "" || port == "" || addr == "" {
+			return nil, errs.WithSuffix(ErrInvalidResolve, fmt.Sprintf("(%s)", entry))
+		}
+		addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+		overrides[net.JoinHostPort(host, port)] = net.JoinHostPort(addr, port)
+	}
+	return overrides, nil
+}
@@ -0,0 +1,92 @@
+package requester
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpWithCookies_Transport(t *testing.T) {
+	client := NewHttpWithCookies(func(client *http.Client) {
+		client.Transport = &http.Transport{}
+	})
+
+	get := func(t *testing.T, url string, opt TransportOptions) string {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		res, err := client.Do(req, Options{TransportOptions: opt})
+		require.Nil(t, err)
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return string(body)
+	}
+
+	t.Run("unix-socket", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "app.sock")
+		lis, err := net.Listen("unix", socket)
+		require.Nil(t, err)
+
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "unix "+r.Host)
+		}))
+		srv.Listener = lis
+		srv.Start()
+		defer srv.Close()
+
+		assert.Equal(t, "unix app.local", get(t, "http://app.local/", TransportOptions{UnixSocket: "unix://" + socket}))
+		assert.Equal(t, "unix app.local", get(t, "http://app.local/", TransportOptions{UnixSocket: socket}))
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "resolved "+r.Host)
+		}))
+		defer srv.Close()
+
+		_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+		assert.Equal(t, "resolved example.test:"+port, get(t, "http://example.test:"+port+"/",
+			TransportOptions{Resolve: []string{"example.test:" + port + ":127.0.0.1"}}))
+	})
+
+	t.Run("proxy", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "proxied "+r.URL.String())
+		}))
+		defer proxy.Close()
+
+		assert.Equal(t, "proxied http://example.test/foo", get(t, "http://example.test/foo",
+			TransportOptions{Proxy: proxy.URL}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.test", nil)
+
+		_, err := client.Do(req, Options{TransportOptions: TransportOptions{Proxy: "ftp://proxy"}})
+		assert.ErrorIs(t, err, ErrInvalidProxyURL)
+
+		_, err = client.Do(req, Options{TransportOptions: TransportOptions{Resolve: []string{"example.test:80"}}})
+		assert.ErrorIs(t, err, ErrInvalidResolve)
+
+		_, err = client.Do(req, Options{TransportOptions: TransportOptions{UnixSocket: "http://foo"}})
+		assert.ErrorIs(t, err, ErrInvalidSocketURL)
+	})
+}
+
+func TestOptionsFromMap_Transport(t *testing.T) {
+	opt := OptionsFromMap(map[string]any{
+		"proxy":      "socks5://localhost:1080",
+		"unixsocket": "unix:///var/run/app.sock",
+		"resolve":    []any{"example.com:443:127.0.0.1"},
+	})
+	assert.Equal(t, TransportOptions{
+		Proxy:      "socks5://localhost:1080",
+		UnixSocket: "unix:///var/run/app.sock",
+		Resolve:    []string{"example.com:443:127.0.0.1"},
+	}, opt.TransportOptions)
+}
@@ -0,0 +1,34 @@
+package stdlib
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	nowMtx   sync.RWMutex
+	frozenAt time.Time
+)
+
+// Now returns the time used by all builtins depending
+// on the current time. This is the current local time
+// unless it has been overridden using SetNow.
+func Now() time.Time {
+	nowMtx.RLock()
+	defer nowMtx.RUnlock()
+
+	if frozenAt.IsZero() {
+		return time.Now()
+	}
+	return frozenAt
+}
+
+// SetNow overrides the time returned by Now with the
+// given time t. Passing the zero time restores the
+// current local time.
+func SetNow(t time.Time) {
+	nowMtx.Lock()
+	defer nowMtx.Unlock()
+
+	frozenAt = t
+}
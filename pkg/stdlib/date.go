@@ -0,0 +1,38 @@
+package stdlib
+
+import (
+	"strings"
+	"time"
+)
+
+var dateFormats = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UNIXDATE":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339NANO": time.RFC3339Nano,
+	"KITCHEN":     time.Kitchen,
+	"STAMP":       time.Stamp,
+	"STAMPMILLI":  time.StampMilli,
+	"STAMPMICRO":  time.StampMicro,
+	"STAMPNANO":   time.StampNano,
+	"DATETIME":    time.DateTime,
+	"DATEONLY":    time.DateOnly,
+	"TIMEONLY":    time.TimeOnly,
+}
+
+// DateFormat returns the Go time layout of the predefined
+// format with the given name, like RFC3339 or DateOnly. If
+// there is no predefined format with the given name, the
+// name is returned as layout.
+func DateFormat(format string) string {
+	if trans, ok := dateFormats[strings.ToUpper(format)]; ok {
+		return trans
+	}
+	return format
+}
@@ -0,0 +1,65 @@
+package stdlib
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// Base64 encodes v using standard, padded base64 encoding.
+func Base64(v string) string {
+	return base64.StdEncoding.EncodeToString([]byte(v))
+}
+
+// Base64Url encodes v using padded, URL safe base64 encoding.
+func Base64Url(v string) string {
+	return base64.URLEncoding.EncodeToString([]byte(v))
+}
+
+// Base64Unpadded encodes v using standard base64 encoding
+// without padding.
+func Base64Unpadded(v string) string {
+	return base64.RawStdEncoding.EncodeToString([]byte(v))
+}
+
+// Base64UrlUnpadded encodes v using URL safe base64 encoding
+// without padding.
+func Base64UrlUnpadded(v string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(v))
+}
+
+// Base64Decode decodes v, which can either be standard or
+// URL safe base64 encoded with or without padding.
+func Base64Decode(v string) (string, error) {
+	data, err := decodeBase64(v)
+	return string(data), err
+}
+
+func decodeBase64(v string) ([]byte, error) {
+	v = strings.TrimRight(v, "=")
+	v = strings.NewReplacer("-", "+", "_", "/").Replace(v)
+	return base64.RawStdEncoding.DecodeString(v)
+}
+
+// Hex encodes v as lower case hex string.
+func Hex(v string) string {
+	return hex.EncodeToString([]byte(v))
+}
+
+// HexDecode decodes the hex encoded string v.
+func HexDecode(v string) (string, error) {
+	data, err := hex.DecodeString(v)
+	return string(data), err
+}
+
+// UrlEncode escapes v so that it can be safely placed
+// inside a URL query.
+func UrlEncode(v string) string {
+	return url.QueryEscape(v)
+}
+
+// UrlDecode reverses UrlEncode.
+func UrlDecode(v string) (string, error) {
+	return url.QueryUnescape(v)
+}
@@ -0,0 +1,56 @@
+package stdlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64Decode(t *testing.T) {
+	for _, encoded := range []string{
+		Base64("hello goat?>"),
+		Base64Url("hello goat?>"),
+		Base64Unpadded("hello goat?>"),
+		Base64UrlUnpadded("hello goat?>"),
+	} {
+		decoded, err := Base64Decode(encoded)
+		require.Nil(t, err, encoded)
+		assert.Equal(t, "hello goat?>", decoded, encoded)
+	}
+
+	_, err := Base64Decode("not base64!")
+	assert.Error(t, err)
+}
+
+func TestHex(t *testing.T) {
+	assert.Equal(t, "676f6174", Hex("goat"))
+
+	decoded, err := HexDecode("676F6174")
+	require.Nil(t, err)
+	assert.Equal(t, "goat", decoded)
+
+	_, err = HexDecode("xyz")
+	assert.Error(t, err)
+}
+
+func TestUrlEncode(t *testing.T) {
+	assert.Equal(t, "a+b%26c%3Dd%2F%C3%A4", UrlEncode("a b&c=d/ä"))
+
+	decoded, err := UrlDecode("a+b%26c%3Dd%2F%C3%A4")
+	require.Nil(t, err)
+	assert.Equal(t, "a b&c=d/ä", decoded)
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed", Hasher("sha1")("hello world"))
+	assert.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", Hasher("sha256")("hello world"))
+	assert.Panics(t, func() { Hasher("sha3") })
+
+	mac, err := Hmac("sha256", "key", "The quick brown fox jumps over the lazy dog")
+	require.Nil(t, err)
+	assert.Equal(t, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", mac)
+
+	_, err = Hmac("sha3", "key", "message")
+	assert.ErrorIs(t, err, ErrUnsupportedHash)
+}
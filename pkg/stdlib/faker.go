@@ -0,0 +1,310 @@
+package stdlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+var (
+	ErrUnsupportedLocale = errors.New("unsupported locale")
+	ErrInvalidDateRange  = errors.New("the end of the date range must not be before its start")
+	ErrNoValues          = errors.New("no values to pick from")
+)
+
+const defaultLocale = "en"
+
+// Address is a generated postal address.
+type Address struct {
+	Street     string
+	PostalCode string
+	City       string
+	Country    string
+}
+
+func (t Address) String() string {
+	return fmt.Sprintf("%s, %s %s, %s", t.Street, t.PostalCode, t.City, t.Country)
+}
+
+// fakerLocaleOf returns the faker data for the given
+// optional locale. Region suffixes like in "de-AT" or
+// "en_US" are ignored.
+func fakerLocaleOf(locale []string) (string, *fakerLocale, error) {
+	name := defaultLocale
+	if len(locale) != 0 && locale[0] != "" {
+		name = strings.ToLower(locale[0])
+		if i := strings.IndexAny(name, "-_"); i != -1 {
+			name = name[:i]
+		}
+	}
+
+	l, ok := fakerLocales[name]
+	if !ok {
+		return "", nil, errs.WithSuffix(ErrUnsupportedLocale, fmt.Sprintf("(%s)", locale[0]))
+	}
+
+	return name, l, nil
+}
+
+func pick[T any](values []T) T {
+	return values[rng.Intn(len(values))]
+}
+
+// digits replaces each '#' in the given pattern
+// with a random digit.
+func digits(pattern string) string {
+	var sb strings.Builder
+	for _, r := range pattern {
+		if r == '#' {
+			sb.WriteByte(byte('0' + rng.Intn(10)))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// FakeFirstName returns a random first name
+// for the given locale.
+func FakeFirstName(locale ...string) (string, error) {
+	_, l, err := fakerLocaleOf(locale)
+	if err != nil {
+		return "", err
+	}
+	return pick(l.firstNames), nil
+}
+
+// FakeLastName returns a random last name
+// for the given locale.
+func FakeLastName(locale ...string) (string, error) {
+	_, l, err := fakerLocaleOf(locale)
+	if err != nil {
+		return "", err
+	}
+	return pick(l.lastNames), nil
+}
+
+// FakeName returns a random full name
+// for the given locale.
+func FakeName(locale ...string) (string, error) {
+	_, l, err := fakerLocaleOf(locale)
+	if err != nil {
+		return "", err
+	}
+	return pick(l.firstNames) + " " + pick(l.lastNames), nil
+}
+
+// FakeEmail returns a random email address based on
+// a name of the given locale. Only domains reserved
+// for testing are used.
+func FakeEmail(locale ...string) (string, error) {
+	_, l, err := fakerLocaleOf(locale)
+	if err != nil {
+		return "", err
+	}
+
+	local := fmt.Sprintf("%s.%s%d",
+		asciiLower(pick(l.firstNames)), asciiLower(pick(l.lastNames)), rng.Intn(100))
+	return local + "@" + pick(emailDomains), nil
+}
+
+// transliterations replace the non-ASCII letters
+// used in the locale data.
+var transliterations = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"à", "a", "â", "a", "ç", "c", "é", "e", "è", "e", "ê", "e",
+	"ë", "e", "î", "i", "ï", "i", "ô", "o", "ù", "u", "û", "u",
+)
+
+// asciiLower returns v in lower case with non-ASCII
+// letters transliterated and all other characters
+// removed.
+func asciiLower(v string) string {
+	var sb strings.Builder
+	for _, r := range transliterations.Replace(strings.ToLower(v)) {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// FakePhone returns a random phone number
+// formatted for the given locale.
+func FakePhone(locale ...string) (string, error) {
+	_, l, err := fakerLocaleOf(locale)
+	if err != nil {
+		return "", err
+	}
+	return digits(l.phone), nil
+}
+
+// FakeAddress returns a random postal address
+// for the given locale.
+func FakeAddress(locale ...string) (Address, error) {
+	_, l, err := fakerLocaleOf(locale)
+	if err != nil {
+		return Address{}, err
+	}
+
+	var a Address
+	number := rng.Intn(199) + 1
+	if l.streetFirst {
+		a.Street = fmt.Sprintf("%s %d", pick(l.streets), number)
+	} else {
+		a.Street = fmt.Sprintf("%d %s", number, pick(l.streets))
+	}
+	a.PostalCode = digits(l.postcode)
+	a.City = pick(l.cities)
+	a.Country = l.country
+
+	return a, nil
+}
+
+// FakeIban returns a random IBAN with valid check
+// digits of the country of the given locale.
+func FakeIban(locale ...string) (string, error) {
+	name, l, err := fakerLocaleOf(locale)
+	if err != nil {
+		return "", err
+	}
+
+	country := fakerIbanCountries[name]
+	bban := digits(l.bban)
+
+	return country + ibanCheckDigits(country, bban) + bban, nil
+}
+
+// ibanCheckDigits calculates the ISO 7064 MOD 97-10
+// check digits of an IBAN.
+func ibanCheckDigits(country, bban string) string {
+	var numeric strings.Builder
+	for _, r := range bban + country + "00" {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&numeric, "%d", r-'A'+10)
+		} else {
+			numeric.WriteRune(r)
+		}
+	}
+
+	n, _ := new(big.Int).SetString(numeric.String(), 10)
+	mod := new(big.Int).Mod(n, big.NewInt(97)).Int64()
+
+	return fmt.Sprintf("%02d", 98-mod)
+}
+
+// FakeWords returns n random lorem ipsum words
+// separated by spaces. The default is 5 words.
+func FakeWords(n ...int) string {
+	count := 5
+	if len(n) != 0 {
+		count = n[0]
+	}
+
+	words := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		words = append(words, pick(loremWords))
+	}
+
+	return strings.Join(words, " ")
+}
+
+// FakeSentence returns a random lorem ipsum sentence
+// with the given number of words. By default, the
+// sentence has between 6 and 12 words.
+func FakeSentence(n ...int) string {
+	count := 6 + rng.Intn(7)
+	if len(n) != 0 {
+		count = n[0]
+	}
+
+	sentence := FakeWords(count)
+	if sentence == "" {
+		return ""
+	}
+
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+// FakeParagraph returns a random lorem ipsum paragraph
+// with the given number of sentences (3 by default).
+func FakeParagraph(n ...int) string {
+	count := 3
+	if len(n) != 0 {
+		count = n[0]
+	}
+
+	sentences := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		sentences = append(sentences, FakeSentence())
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// FakeDate returns a random time between from and to
+// formatted in the given format. From and to must be
+// formatted as RFC 3339 timestamp or date (2006-01-02).
+//
+// If no format is passed, the date is formatted like
+// the bounds.
+func FakeDate(from, to string, format ...string) (string, error) {
+	start, startFormat, err := parseDateBound(from)
+	if err != nil {
+		return "", err
+	}
+	end, _, err := parseDateBound(to)
+	if err != nil {
+		return "", err
+	}
+
+	if end.Before(start) {
+		return "", ErrInvalidDateRange
+	}
+
+	date := start.Add(time.Duration(rng.Int63n(int64(end.Sub(start)) + 1)))
+
+	if len(format) != 0 {
+		return date.Format(DateFormat(format[0])), nil
+	}
+	return date.Format(startFormat), nil
+}
+
+func parseDateBound(v string) (time.Time, string, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, time.DateOnly, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, "", errs.WithPrefix("invalid date:", err)
+	}
+	return t, time.RFC3339, nil
+}
+
+// FakePick returns one of the given values at random.
+// If a single list is passed, one of its elements is
+// returned.
+func FakePick(values ...any) (any, error) {
+	if len(values) == 1 {
+		v := reflect.ValueOf(values[0])
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			if v.Len() == 0 {
+				return nil, ErrNoValues
+			}
+			return v.Index(rng.Intn(v.Len())).Interface(), nil
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, ErrNoValues
+	}
+
+	return pick(values), nil
+}
@@ -0,0 +1,123 @@
+package stdlib
+
+// fakerLocale contains the data used to generate
+// fake data for a locale.
+type fakerLocale struct {
+	firstNames []string
+	lastNames  []string
+	streets    []string
+	cities     []string
+	country    string
+
+	// postcode, phone and bban are patterns where
+	// each '#' is replaced by a random digit.
+	postcode string
+	phone    string
+	bban     string
+
+	// streetFirst specifies if the house number
+	// follows the street name.
+	streetFirst bool
+}
+
+var fakerLocales = map[string]*fakerLocale{
+	"en": {
+		firstNames: []string{
+			"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda",
+			"William", "Elizabeth", "David", "Barbara", "Richard", "Susan", "Joseph", "Jessica",
+			"Thomas", "Sarah", "Charles", "Karen", "Daniel", "Emily", "Matthew", "Olivia",
+		},
+		lastNames: []string{
+			"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis",
+			"Wilson", "Anderson", "Taylor", "Thomas", "Moore", "Jackson", "Martin", "Lee",
+			"Thompson", "White", "Harris", "Clark", "Lewis", "Walker", "Hall", "Young",
+		},
+		streets: []string{
+			"Main Street", "Oak Avenue", "Maple Drive", "Cedar Lane", "Park Road", "Elm Street",
+			"Washington Avenue", "Lake Drive", "Hill Street", "Pine Road", "Sunset Boulevard",
+		},
+		cities: []string{
+			"Springfield", "Riverside", "Franklin", "Greenville", "Bristol", "Clinton",
+			"Fairview", "Salem", "Madison", "Georgetown", "Arlington", "Ashland",
+		},
+		country:     "United States",
+		postcode:    "#####",
+		phone:       "+1 555-###-####",
+		bban:        "NWBK##############",
+		streetFirst: false,
+	},
+	"de": {
+		firstNames: []string{
+			"Maximilian", "Sophie", "Alexander", "Marie", "Paul", "Emilia", "Leon", "Hannah",
+			"Lukas", "Mia", "Felix", "Lena", "Jonas", "Anna", "Elias", "Lea",
+			"Noah", "Laura", "Finn", "Johanna", "Moritz", "Clara", "Jakob", "Charlotte",
+		},
+		lastNames: []string{
+			"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker",
+			"Schulz", "Hoffmann", "Schäfer", "Koch", "Bauer", "Richter", "Klein", "Wolf",
+			"Schröder", "Neumann", "Schwarz", "Zimmermann", "Braun", "Krüger", "Hofmann", "Hartmann",
+		},
+		streets: []string{
+			"Hauptstraße", "Schulstraße", "Gartenstraße", "Bahnhofstraße", "Dorfstraße", "Bergstraße",
+			"Lindenstraße", "Kirchstraße", "Waldstraße", "Ringstraße", "Goethestraße",
+		},
+		cities: []string{
+			"Berlin", "Hamburg", "München", "Köln", "Frankfurt am Main", "Stuttgart",
+			"Düsseldorf", "Leipzig", "Dortmund", "Essen", "Bremen", "Dresden",
+		},
+		country:     "Deutschland",
+		postcode:    "#####",
+		phone:       "+49 30 #######",
+		bban:        "##################",
+		streetFirst: true,
+	},
+	"fr": {
+		firstNames: []string{
+			"Gabriel", "Louise", "Raphaël", "Ambre", "Léo", "Alice", "Louis", "Jade",
+			"Lucas", "Emma", "Jules", "Chloé", "Adam", "Léa", "Hugo", "Manon",
+			"Arthur", "Inès", "Nathan", "Camille", "Paul", "Sarah", "Victor", "Juliette",
+		},
+		lastNames: []string{
+			"Martin", "Bernard", "Thomas", "Petit", "Robert", "Richard", "Durand", "Dubois",
+			"Moreau", "Laurent", "Simon", "Michel", "Lefebvre", "Leroy", "Roux", "David",
+			"Bertrand", "Morel", "Fournier", "Girard", "Bonnet", "Dupont", "Lambert", "Fontaine",
+		},
+		streets: []string{
+			"Rue de la Paix", "Avenue Victor Hugo", "Rue du Moulin", "Rue de l'Église", "Place de la Mairie",
+			"Rue Pasteur", "Boulevard Voltaire", "Rue des Écoles", "Avenue Jean Jaurès", "Rue Nationale",
+		},
+		cities: []string{
+			"Paris", "Marseille", "Lyon", "Toulouse", "Nice", "Nantes",
+			"Strasbourg", "Montpellier", "Bordeaux", "Lille", "Rennes", "Reims",
+		},
+		country:     "France",
+		postcode:    "#####",
+		phone:       "+33 1 ## ## ## ##",
+		bban:        "#######################",
+		streetFirst: false,
+	},
+}
+
+// fakerIbanCountries maps the locales to the
+// country codes used for generated IBANs.
+var fakerIbanCountries = map[string]string{
+	"en": "GB",
+	"de": "DE",
+	"fr": "FR",
+}
+
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit",
+	"sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore",
+	"magna", "aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud",
+	"exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea", "commodo",
+	"consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate", "velit",
+	"esse", "cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint", "occaecat",
+	"cupidatat", "non", "proident", "sunt", "culpa", "qui", "officia", "deserunt",
+	"mollit", "anim", "id", "est", "laborum",
+}
+
+// emailDomains are reserved for documentation and
+// testing purposes (RFC 2606), so that generated
+// addresses never reach real mailboxes.
+var emailDomains = []string{"example.com", "example.org", "example.net"}
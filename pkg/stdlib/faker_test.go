@@ -0,0 +1,142 @@
+package stdlib
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeName(t *testing.T) {
+	name, err := FakeName("de-DE")
+	require.Nil(t, err)
+	first, last, ok := strings.Cut(name, " ")
+	require.True(t, ok)
+	assert.Contains(t, fakerLocales["de"].firstNames, first)
+	assert.Contains(t, fakerLocales["de"].lastNames, last)
+
+	name, err = FakeFirstName()
+	require.Nil(t, err)
+	assert.Contains(t, fakerLocales["en"].firstNames, name)
+
+	_, err = FakeName("xx")
+	assert.ErrorIs(t, err, ErrUnsupportedLocale)
+}
+
+func TestFakeEmail(t *testing.T) {
+	for _, locale := range []string{"en", "de", "fr"} {
+		for i := 0; i < 20; i++ {
+			email, err := FakeEmail(locale)
+			require.Nil(t, err)
+			assert.Regexp(t, `^[a-z]+\.[a-z]+\d{0,2}@example\.(com|org|net)$`, email)
+		}
+	}
+
+	assert.Equal(t, "muellerschroeder", asciiLower("Müller-Schröder"))
+	assert.Equal(t, "raphael", asciiLower("Raphaël"))
+}
+
+func TestFakePhone(t *testing.T) {
+	phone, err := FakePhone("en")
+	require.Nil(t, err)
+	assert.Regexp(t, `^\+1 555-\d{3}-\d{4}$`, phone)
+
+	phone, err = FakePhone("fr")
+	require.Nil(t, err)
+	assert.Regexp(t, `^\+33 1( \d{2}){4}$`, phone)
+}
+
+func TestFakeAddress(t *testing.T) {
+	address, err := FakeAddress("de")
+	require.Nil(t, err)
+	assert.Regexp(t, `^\D+ \d+$`, address.Street)
+	assert.Regexp(t, `^\d{5}$`, address.PostalCode)
+	assert.Contains(t, fakerLocales["de"].cities, address.City)
+	assert.Equal(t, "Deutschland", address.Country)
+	assert.Equal(t, address.Street+", "+address.PostalCode+" "+address.City+", Deutschland", address.String())
+
+	address, err = FakeAddress("en")
+	require.Nil(t, err)
+	assert.Regexp(t, `^\d+ \D+$`, address.Street)
+}
+
+func TestFakeIban(t *testing.T) {
+	assert.Equal(t, "82", ibanCheckDigits("GB", "WEST12345698765432"))
+
+	lengths := map[string]int{"en": 22, "de": 22, "fr": 27}
+	for locale, length := range lengths {
+		iban, err := FakeIban(locale)
+		require.Nil(t, err)
+		assert.Len(t, iban, length, iban)
+		assert.True(t, validIban(iban), iban)
+	}
+}
+
+func TestFakeLorem(t *testing.T) {
+	words := FakeWords(4)
+	assert.Len(t, strings.Fields(words), 4)
+
+	sentence := FakeSentence(3)
+	assert.Regexp(t, `^[A-Z][a-z]* [a-z]+ [a-z]+\.$`, sentence)
+
+	paragraph := FakeParagraph(2)
+	assert.Equal(t, 2, strings.Count(paragraph, "."))
+}
+
+func TestFakeDate(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		v, err := FakeDate("2024-01-01", "2024-01-31")
+		require.Nil(t, err)
+		date, err := time.Parse(time.DateOnly, v)
+		require.Nil(t, err)
+		assert.Equal(t, time.January, date.Month())
+		assert.Equal(t, 2024, date.Year())
+	}
+
+	v, err := FakeDate("2024-01-01T10:00:00Z", "2024-01-01T10:00:00Z")
+	require.Nil(t, err)
+	assert.Equal(t, "2024-01-01T10:00:00Z", v)
+
+	v, err = FakeDate("2024-01-01", "2024-01-01", "rfc1123")
+	require.Nil(t, err)
+	assert.Equal(t, "Mon, 01 Jan 2024 00:00:00 UTC", v)
+
+	_, err = FakeDate("2024-02-01", "2024-01-01")
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+	_, err = FakeDate("yesterday", "2024-01-01")
+	assert.Error(t, err)
+}
+
+func TestFakePick(t *testing.T) {
+	v, err := FakePick("a", "b", "c")
+	require.Nil(t, err)
+	assert.Contains(t, []any{"a", "b", "c"}, v)
+
+	v, err = FakePick([]any{int64(1), int64(2)})
+	require.Nil(t, err)
+	assert.Contains(t, []any{int64(1), int64(2)}, v)
+
+	_, err = FakePick()
+	assert.ErrorIs(t, err, ErrNoValues)
+
+	_, err = FakePick([]string{})
+	assert.ErrorIs(t, err, ErrNoValues)
+}
+
+func validIban(iban string) bool {
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			numeric.WriteString(big.NewInt(int64(r - 'A' + 10)).String())
+		} else {
+			numeric.WriteRune(r)
+		}
+	}
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
@@ -0,0 +1,67 @@
+package stdlib
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+var (
+	ErrUnsupportedHash = errors.New("unsupported hash algorithm")
+)
+
+var hashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+func newHash(algorithm string) (func() hash.Hash, error) {
+	newHash, ok := hashes[strings.ToLower(algorithm)]
+	if !ok {
+		return nil, errs.WithSuffix(ErrUnsupportedHash, fmt.Sprintf("(%s)", algorithm))
+	}
+	return newHash, nil
+}
+
+// Hasher returns a function returning the hex encoded
+// hash of the passed string using the given algorithm.
+//
+// Supported algorithms are md5, sha1, sha256, sha384
+// and sha512. Hasher panics on other algorithms.
+func Hasher(algorithm string) func(string) string {
+	newHash, err := newHash(algorithm)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(s string) string {
+		hsh := newHash()
+		io.WriteString(hsh, s)
+		return fmt.Sprintf("%x", hsh.Sum(nil))
+	}
+}
+
+// Hmac returns the hex encoded HMAC of message using
+// the given key and hash algorithm.
+func Hmac(algorithm, key, message string) (string, error) {
+	newHash, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(newHash, []byte(key))
+	io.WriteString(mac, message)
+	return fmt.Sprintf("%x", mac.Sum(nil)), nil
+}
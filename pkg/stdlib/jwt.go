@@ -0,0 +1,304 @@
+package stdlib
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+var (
+	ErrInvalidJwt           = errors.New("invalid jwt: must consist of three base64url encoded parts")
+	ErrUnsupportedJwtAlg    = errors.New("unsupported jwt algorithm")
+	ErrInvalidPemKey        = errors.New("key must be a PEM encoded key or certificate")
+	ErrKeyAlgorithmMismatch = errors.New("key type does not match the jwt algorithm")
+	ErrJwtAlgMismatch       = errors.New("jwt algorithm does not match the expected algorithm")
+)
+
+// jwtAlg describes a JWT signing algorithm.
+type jwtAlg struct {
+	family string
+	hash   crypto.Hash
+}
+
+var jwtAlgs = map[string]jwtAlg{
+	"HS256": {"HS", crypto.SHA256},
+	"HS384": {"HS", crypto.SHA384},
+	"HS512": {"HS", crypto.SHA512},
+	"RS256": {"RS", crypto.SHA256},
+	"RS384": {"RS", crypto.SHA384},
+	"RS512": {"RS", crypto.SHA512},
+	"ES256": {"ES", crypto.SHA256},
+	"ES384": {"ES", crypto.SHA384},
+	"ES512": {"ES", crypto.SHA512},
+}
+
+func jwtAlgOf(name string) (jwtAlg, error) {
+	alg, ok := jwtAlgs[strings.ToUpper(name)]
+	if !ok {
+		return jwtAlg{}, errs.WithSuffix(ErrUnsupportedJwtAlg, fmt.Sprintf("(%s)", name))
+	}
+	return alg, nil
+}
+
+// JwtDecode decodes the given JWT without verifying it and
+// returns a map containing the header, the payload and the
+// signature of the token.
+func JwtDecode(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidJwt
+	}
+
+	var header, payload any
+	if err := decodeJwtPart(parts[0], &header); err != nil {
+		return nil, errs.WithPrefix("failed decoding header:", err)
+	}
+	if err := decodeJwtPart(parts[1], &payload); err != nil {
+		return nil, errs.WithPrefix("failed decoding payload:", err)
+	}
+
+	return map[string]any{
+		"header":    header,
+		"payload":   payload,
+		"signature": parts[2],
+	}, nil
+}
+
+func decodeJwtPart(part string, v any) error {
+	data, err := decodeBase64(part)
+	if err != nil {
+		return ErrInvalidJwt
+	}
+	return json.Unmarshal(data, v)
+}
+
+// JwtSign encodes the given payload as JWT signed with the
+// given key using the given algorithm (HS256 by default).
+//
+// For HS algorithms, the key is the shared secret. For RS
+// and ES algorithms, the key is a PEM encoded private key.
+func JwtSign(payload any, key string, algorithm ...string) (string, error) {
+	algName := "HS256"
+	if len(algorithm) != 0 {
+		algName = strings.ToUpper(algorithm[0])
+	}
+
+	alg, err := jwtAlgOf(algName)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": algName, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(payload)
+	if err != nil {
+		return "", errs.WithPrefix("failed encoding payload:", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+
+	signature, err := alg.sign(signingInput, key)
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// JwtVerify returns true when the signature of the given
+// token is valid for the given key and, if set, the exp and
+// nbf claims are valid at the current time.
+//
+// For HS algorithms, the key is the shared secret. For RS
+// and ES algorithms, the key is a PEM encoded public key or
+// certificate.
+//
+// If algorithm is passed, the alg header of the token must
+// match it. Because the header is controlled by the issuer
+// of the token, HS algorithms are also rejected when the
+// key is PEM encoded, so that a public key can not be used
+// as shared secret to forge tokens.
+func JwtVerify(token string, key string, algorithm ...string) (bool, error) {
+	decoded, err := JwtDecode(token)
+	if err != nil {
+		return false, err
+	}
+
+	header, _ := decoded["header"].(map[string]any)
+	algName, _ := header["alg"].(string)
+	if len(algorithm) != 0 && !strings.EqualFold(algName, algorithm[0]) {
+		return false, errs.WithSuffix(ErrJwtAlgMismatch,
+			fmt.Sprintf("(expected %s, got %s)", algorithm[0], algName))
+	}
+
+	alg, err := jwtAlgOf(algName)
+	if err != nil {
+		return false, err
+	}
+
+	i := strings.LastIndexByte(token, '.')
+	signature, err := decodeBase64(token[i+1:])
+	if err != nil {
+		return false, ErrInvalidJwt
+	}
+
+	ok, err := alg.verify(token[:i], signature, key)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if claims, ok := decoded["payload"].(map[string]any); ok {
+		now := Now().Unix()
+		if exp, ok := claims["exp"].(float64); ok && now >= int64(exp) {
+			return false, nil
+		}
+		if nbf, ok := claims["nbf"].(float64); ok && now < int64(nbf) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (t jwtAlg) digest(signingInput string) []byte {
+	hsh := t.hash.New()
+	hsh.Write([]byte(signingInput))
+	return hsh.Sum(nil)
+}
+
+func (t jwtAlg) sign(signingInput string, key string) ([]byte, error) {
+	if t.family == "HS" {
+		mac := hmac.New(t.hash.New, []byte(key))
+		mac.Write([]byte(signingInput))
+		return mac.Sum(nil), nil
+	}
+
+	privateKey, err := parsePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.family {
+	case "RS":
+		rsaKey, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, ErrKeyAlgorithmMismatch
+		}
+		return rsa.SignPKCS1v15(rand.Reader, rsaKey, t.hash, t.digest(signingInput))
+
+	case "ES":
+		ecKey, ok := privateKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, ErrKeyAlgorithmMismatch
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, t.digest(signingInput))
+		if err != nil {
+			return nil, err
+		}
+		// JWS encodes ECDSA signatures as the concatenation
+		// of r and s, each padded to the size of the curve.
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+		return signature, nil
+	}
+
+	return nil, ErrUnsupportedJwtAlg
+}
+
+func (t jwtAlg) verify(signingInput string, signature []byte, key string) (bool, error) {
+	if t.family == "HS" {
+		if block, _ := pem.Decode([]byte(key)); block != nil {
+			return false, ErrKeyAlgorithmMismatch
+		}
+		expected, _ := t.sign(signingInput, key)
+		return hmac.Equal(signature, expected), nil
+	}
+
+	publicKey, err := parsePublicKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	switch t.family {
+	case "RS":
+		rsaKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return false, ErrKeyAlgorithmMismatch
+		}
+		return rsa.VerifyPKCS1v15(rsaKey, t.hash, t.digest(signingInput), signature) == nil, nil
+
+	case "ES":
+		ecKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return false, ErrKeyAlgorithmMismatch
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false, nil
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(ecKey, t.digest(signingInput), r, s), nil
+	}
+
+	return false, ErrUnsupportedJwtAlg
+}
+
+// parsePrivateKey parses a PEM encoded PKCS #8, PKCS #1
+// or SEC 1 private key.
+func parsePrivateKey(key string) (any, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, ErrInvalidPemKey
+	}
+
+	if k, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+
+	return nil, ErrInvalidPemKey
+}
+
+// parsePublicKey parses a PEM encoded PKIX or PKCS #1
+// public key or the public key of a certificate.
+func parsePublicKey(key string) (any, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, ErrInvalidPemKey
+	}
+
+	if k, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		return cert.PublicKey, nil
+	}
+
+	return nil, ErrInvalidPemKey
+}
@@ -0,0 +1,143 @@
+package stdlib
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJwt = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." +
+	"eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiaWF0IjoxNTE2MjM5MDIyfQ." +
+	"SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c"
+
+func TestJwtDecode(t *testing.T) {
+	decoded, err := JwtDecode(testJwt)
+	require.Nil(t, err)
+
+	assert.Equal(t, map[string]any{"alg": "HS256", "typ": "JWT"}, decoded["header"])
+	assert.Equal(t, map[string]any{"sub": "1234567890", "name": "John Doe", "iat": 1516239022.0}, decoded["payload"])
+	assert.Equal(t, "SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c", decoded["signature"])
+
+	_, err = JwtDecode("foo.bar")
+	assert.ErrorIs(t, err, ErrInvalidJwt)
+}
+
+func TestJwtVerify_HS(t *testing.T) {
+	ok, err := JwtVerify(testJwt, "your-256-bit-secret")
+	require.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = JwtVerify(testJwt, "wrong-secret")
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	token, err := JwtSign(map[string]any{"sub": "goat"}, "secret", "HS512")
+	require.Nil(t, err)
+	ok, err = JwtVerify(token, "secret")
+	require.Nil(t, err)
+	assert.True(t, ok)
+
+	expired, err := JwtSign(map[string]any{"exp": time.Now().Add(-time.Minute).Unix()}, "secret")
+	require.Nil(t, err)
+	ok, err = JwtVerify(expired, "secret")
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	_, err = JwtSign(map[string]any{}, "secret", "none")
+	assert.ErrorIs(t, err, ErrUnsupportedJwtAlg)
+}
+
+func TestJwtSign_Asymmetric(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.Nil(t, err)
+
+	rsaPrivate := pemEncode(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	rsaPublic := pemEncode(t, "PUBLIC KEY", mustMarshalPKIX(t, &rsaKey.PublicKey))
+	ecPrivateDer, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.Nil(t, err)
+	ecPrivate := pemEncode(t, "PRIVATE KEY", ecPrivateDer)
+	ecPublic := pemEncode(t, "PUBLIC KEY", mustMarshalPKIX(t, &ecKey.PublicKey))
+
+	cases := []struct {
+		alg             string
+		private, public string
+	}{
+		{"RS256", rsaPrivate, rsaPublic},
+		{"RS512", rsaPrivate, rsaPublic},
+		{"ES384", ecPrivate, ecPublic},
+	}
+
+	for _, c := range cases {
+		token, err := JwtSign(map[string]any{"sub": "goat"}, c.private, c.alg)
+		require.Nil(t, err, c.alg)
+
+		decoded, err := JwtDecode(token)
+		require.Nil(t, err, c.alg)
+		assert.Equal(t, c.alg, decoded["header"].(map[string]any)["alg"])
+
+		ok, err := JwtVerify(token, c.public)
+		require.Nil(t, err, c.alg)
+		assert.True(t, ok, c.alg)
+
+		tampered := token[:len(token)-4] + "AAAA"
+		ok, _ = JwtVerify(tampered, c.public)
+		assert.False(t, ok, c.alg)
+	}
+
+	token, err := JwtSign(map[string]any{"sub": "goat"}, rsaPrivate, "RS256")
+	require.Nil(t, err)
+	ok, err := JwtVerify(token, rsaPublic, "RS256")
+	require.Nil(t, err)
+	assert.True(t, ok)
+
+	_, err = JwtSign(map[string]any{}, ecPrivate, "RS256")
+	assert.ErrorIs(t, err, ErrKeyAlgorithmMismatch)
+
+	_, err = JwtSign(map[string]any{}, "no pem", "RS256")
+	assert.ErrorIs(t, err, ErrInvalidPemKey)
+}
+
+func TestJwtVerify_AlgConfusion(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	rsaPublic := pemEncode(t, "PUBLIC KEY", mustMarshalPKIX(t, &rsaKey.PublicKey))
+
+	// A token forged by using the public key, which is
+	// known to an attacker, as the HMAC secret.
+	forged, err := JwtSign(map[string]any{"sub": "admin"}, rsaPublic, "HS256")
+	require.Nil(t, err)
+
+	ok, err := JwtVerify(forged, rsaPublic)
+	assert.ErrorIs(t, err, ErrKeyAlgorithmMismatch)
+	assert.False(t, ok)
+
+	ok, err = JwtVerify(forged, rsaPublic, "RS256")
+	assert.ErrorIs(t, err, ErrJwtAlgMismatch)
+	assert.False(t, ok)
+
+	ok, err = JwtVerify(testJwt, "your-256-bit-secret", "RS256")
+	assert.ErrorIs(t, err, ErrJwtAlgMismatch)
+	assert.False(t, ok)
+}
+
+func pemEncode(t *testing.T, typ string, der []byte) string {
+	t.Helper()
+	return string(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}))
+}
+
+func mustMarshalPKIX(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.Nil(t, err)
+	return der
+}
@@ -0,0 +1,181 @@
+package stdlib
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/studio-b12/goat/pkg/errs"
+)
+
+var (
+	ErrUnsupportedEncoding = errors.New("unsupported encoding, must be 'hex' or 'base64'")
+)
+
+// lockedSource is a rand.Source which can be
+// used concurrently.
+type lockedSource struct {
+	mtx sync.Mutex
+	src rand.Source64
+}
+
+func (t *lockedSource) Int63() int64 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.src.Int63()
+}
+
+func (t *lockedSource) Uint64() uint64 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.src.Uint64()
+}
+
+func (t *lockedSource) Seed(seed int64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.src.Seed(seed)
+}
+
+var rng = rand.New(&lockedSource{
+	src: rand.NewSource(time.Now().UnixNano()).(rand.Source64),
+})
+
+// seeded is true if the seed has been set explicitly
+// via Seed.
+var seeded atomic.Bool
+
+// Seed seeds the random number generator used by all
+// builtins generating random data, so that the same
+// seed results in the same sequence of values. This
+// includes RandomBytes and UuidV4, which are therefore
+// not secret anymore and must not be used as such.
+//
+// Seed must not be called concurrently with any of
+// the builtins.
+func Seed(seed int64) {
+	rng.Seed(seed)
+	seeded.Store(true)
+}
+
+// SeedDefault seeds the random number generator like
+// Seed, but RandomBytes and UuidV4 keep being generated
+// using crypto/rand. It is used when no seed has been
+// set explicitly.
+//
+// SeedDefault must not be called concurrently with any
+// of the builtins.
+func SeedDefault(seed int64) {
+	rng.Seed(seed)
+	seeded.Store(false)
+}
+
+// readSecret fills buf with random bytes from crypto/rand
+// or from the seeded random number generator, if the
+// seed has been set explicitly.
+func readSecret(buf []byte) {
+	if seeded.Load() {
+		rng.Read(buf)
+		return
+	}
+	cryptorand.Read(buf)
+}
+
+// Float64 returns a random number in the range [0.0, 1.0)
+// drawn from the seeded random number generator.
+func Float64() float64 {
+	return rng.Float64()
+}
+
+// RandomString returns a random alphanumeric string of the
+// given length. The default length is 8.
+func RandomString(lnOpt ...int) string {
+	const defaultLen = 8
+	const charSet = "abcdefhijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+
+	ln := defaultLen
+	if len(lnOpt) != 0 {
+		ln = lnOpt[0]
+	}
+
+	buf := make([]byte, ln)
+	for i := 0; i < ln; i++ {
+		buf[i] = charSet[rng.Intn(len(charSet))]
+	}
+
+	return string(buf)
+}
+
+// RandomInt returns a random non-negative integer. If n
+// is passed, the integer is in the range [0, n).
+func RandomInt(nOpt ...int) int {
+	if len(nOpt) != 0 {
+		return rng.Intn(nOpt[0])
+	}
+
+	return rng.Int()
+}
+
+// RandomBytes returns n random bytes encoded with the
+// given encoding, which is either hex (default) or base64.
+//
+// The bytes are generated using crypto/rand unless the
+// seed has been set explicitly via Seed. Seeded output is
+// reproducible and therefore not secret.
+func RandomBytes(n int, encoding ...string) (string, error) {
+	buf := make([]byte, n)
+	readSecret(buf)
+
+	enc := "hex"
+	if len(encoding) != 0 {
+		enc = encoding[0]
+	}
+
+	switch enc {
+	case "hex":
+		return hex.EncodeToString(buf), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(buf), nil
+	default:
+		return "", errs.WithSuffix(ErrUnsupportedEncoding, fmt.Sprintf("(%s)", enc))
+	}
+}
+
+// UuidV4 returns a random version 4 UUID. Like RandomBytes,
+// it is generated using crypto/rand unless the seed has been
+// set explicitly via Seed, in which case it is not secret.
+func UuidV4() string {
+	var uuid [16]byte
+	readSecret(uuid[:])
+	return formatUuid(uuid, 4)
+}
+
+// UuidV7 returns a version 7 UUID, which contains the
+// unix timestamp returned by Now in milliseconds, so that UUIDs
+// generated later sort after earlier ones.
+func UuidV7() string {
+	var uuid [16]byte
+	rng.Read(uuid[6:])
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(Now().UnixMilli()))
+	copy(uuid[:6], ts[2:])
+
+	return formatUuid(uuid, 7)
+}
+
+// formatUuid sets the given version and the RFC 9562
+// variant bits and formats the UUID as string.
+func formatUuid(uuid [16]byte, version byte) string {
+	uuid[6] = uuid[6]&0x0f | version<<4
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
@@ -0,0 +1,82 @@
+package stdlib
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomBytes(t *testing.T) {
+	v, err := RandomBytes(16)
+	require.Nil(t, err)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, v)
+
+	v, err = RandomBytes(3, "base64")
+	require.Nil(t, err)
+	assert.Len(t, v, 4)
+
+	_, err = RandomBytes(3, "base32")
+	assert.ErrorIs(t, err, ErrUnsupportedEncoding)
+}
+
+func TestUuid(t *testing.T) {
+	uuidRx := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([47])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	v4 := UuidV4()
+	assert.Equal(t, "4", uuidRx.FindStringSubmatch(v4)[1], v4)
+	assert.NotEqual(t, v4, UuidV4())
+
+	before := UuidV7()
+	time.Sleep(2 * time.Millisecond)
+	after := UuidV7()
+	assert.Equal(t, "7", uuidRx.FindStringSubmatch(before)[1], before)
+	assert.Less(t, before, after)
+}
+
+func TestSeed(t *testing.T) {
+	generate := func() []any {
+		b, err := RandomBytes(8)
+		require.Nil(t, err)
+		name, err := FakeName()
+		require.Nil(t, err)
+		return []any{RandomString(), RandomInt(), b, UuidV4(), name, Float64()}
+	}
+
+	Seed(42)
+	first := generate()
+	Seed(42)
+	assert.Equal(t, first, generate())
+	Seed(43)
+	assert.NotEqual(t, first, generate())
+
+	// Without an explicitly set seed, random bytes
+	// and v4 UUIDs are not derived from the seed.
+	t.Cleanup(func() { SeedDefault(time.Now().UnixNano()) })
+	SeedDefault(42)
+	second := generate()
+	SeedDefault(42)
+	third := generate()
+	assert.Equal(t, second[0], third[0])
+	assert.NotEqual(t, second[2], third[2])
+	assert.NotEqual(t, second[3], third[3])
+}
+
+func TestSetNow(t *testing.T) {
+	t.Cleanup(func() { SetNow(time.Time{}) })
+
+	fixed := time.Date(2024, 2, 29, 12, 30, 0, 0, time.UTC)
+	SetNow(fixed)
+	assert.Equal(t, fixed, Now())
+
+	Seed(1)
+	v7 := UuidV7()
+	Seed(1)
+	assert.Equal(t, v7, UuidV7())
+	assert.Equal(t, "018df4d7-cd40", v7[:13])
+
+	SetNow(time.Time{})
+	assert.WithinDuration(t, time.Now(), Now(), time.Second)
+}
@@ -0,0 +1,45 @@
+// Package stdlib provides the functions which are shared
+// between the template builtins and the script builtins.
+//
+// All functions are registered by their name in Funcs and
+// can be passed as they are to template.FuncMap as well as
+// to Engine.Set.
+package stdlib
+
+// Funcs maps the builtin names to the shared functions.
+var Funcs = map[string]any{
+	"base64":            Base64,
+	"base64Url":         Base64Url,
+	"base64Unpadded":    Base64Unpadded,
+	"base64UrlUnpadded": Base64UrlUnpadded,
+	"base64Decode":      Base64Decode,
+	"hex":               Hex,
+	"hexDecode":         HexDecode,
+	"urlEncode":         UrlEncode,
+	"urlDecode":         UrlDecode,
+	"md5":               Hasher("md5"),
+	"sha1":              Hasher("sha1"),
+	"sha256":            Hasher("sha256"),
+	"sha512":            Hasher("sha512"),
+	"hmac":              Hmac,
+	"randomString":      RandomString,
+	"randomInt":         RandomInt,
+	"randomBytes":       RandomBytes,
+	"uuid":              UuidV4,
+	"uuidv7":            UuidV7,
+	"jwtDecode":         JwtDecode,
+	"jwtVerify":         JwtVerify,
+	"jwtSign":           JwtSign,
+	"fakeFirstName":     FakeFirstName,
+	"fakeLastName":      FakeLastName,
+	"fakeName":          FakeName,
+	"fakeEmail":         FakeEmail,
+	"fakePhone":         FakePhone,
+	"fakeAddress":       FakeAddress,
+	"fakeIban":          FakeIban,
+	"fakeWords":         FakeWords,
+	"fakeSentence":      FakeSentence,
+	"fakeParagraph":     FakeParagraph,
+	"fakeDate":          FakeDate,
+	"fakePick":          FakePick,
+}
@@ -0,0 +1,23 @@
+package util
+
+// StringList returns the given value as list of strings.
+// A single string results in a list containing only this
+// string. Non-string elements of lists are skipped. For
+// all other values, nil is returned.
+func StringList(v any) []string {
+	switch vt := v.(type) {
+	case string:
+		return []string{vt}
+	case []string:
+		return vt
+	case []any:
+		l := make([]string, 0, len(vt))
+		for _, e := range vt {
+			if s, ok := e.(string); ok {
+				l = append(l, s)
+			}
+		}
+		return l
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package watcher provides a simple polling file watcher
+// which reports changes to a set of files and directories.
+package watcher
+
+import (
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Watcher polls the modification times and sizes of a
+// set of files and reports the changed paths. Changes
+// occurring in short succession are collected and
+// reported at once after a debounce period.
+//
+// Directories can be watched as well. Their modification
+// time changes when entries are added or removed.
+type Watcher struct {
+	interval time.Duration
+	debounce time.Duration
+
+	mtx   sync.Mutex
+	files map[string]fileState
+}
+
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// New returns a new Watcher polling with the given
+// interval and collecting changes for the duration
+// of debounce before reporting them.
+func New(interval, debounce time.Duration) *Watcher {
+	return &Watcher{
+		interval: interval,
+		debounce: debounce,
+		files:    make(map[string]fileState),
+	}
+}
+
+// Set replaces the watched paths with the given ones.
+// The current state of newly added paths is used as
+// reference for subsequent changes.
+func (t *Watcher) Set(paths []string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	files := make(map[string]fileState, len(paths))
+	for _, p := range paths {
+		if state, ok := t.files[p]; ok {
+			files[p] = state
+		} else {
+			files[p] = stat(p)
+		}
+	}
+
+	t.files = files
+}
+
+// Watch polls the watched paths until ctx is done and
+// sends the sorted list of changed paths to the returned
+// channel. The channel is closed when ctx is done.
+func (t *Watcher) Watch(ctx context.Context) <-chan []string {
+	cChanged := make(chan []string)
+
+	go func() {
+		defer close(cChanged)
+
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		changed := make(map[string]struct{})
+		var lastChange time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, p := range t.Poll() {
+					changed[p] = struct{}{}
+					lastChange = now
+				}
+
+				if len(changed) == 0 || now.Sub(lastChange) < t.debounce {
+					continue
+				}
+
+				paths := make([]string, 0, len(changed))
+				for p := range changed {
+					paths = append(paths, p)
+				}
+				sort.Strings(paths)
+				changed = make(map[string]struct{})
+
+				select {
+				case cChanged <- paths:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return cChanged
+}
+
+// Poll checks all watched paths once and returns the
+// paths which have changed since the last check.
+func (t *Watcher) Poll() (changed []string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for p, prev := range t.files {
+		curr := stat(p)
+		if curr != prev {
+			t.files[p] = curr
+			changed = append(changed, p)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+func stat(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{
+		exists:  true,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+}
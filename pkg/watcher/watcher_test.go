@@ -0,0 +1,68 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Poll(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.goat")
+	b := filepath.Join(dir, "b.goat")
+
+	require.Nil(t, os.WriteFile(a, []byte("a"), 0o644))
+
+	w := New(time.Millisecond, 0)
+	w.Set([]string{a, b, dir})
+
+	assert.Empty(t, w.Poll())
+
+	require.Nil(t, os.WriteFile(a, []byte("aa"), 0o644))
+	assert.Equal(t, []string{a}, w.Poll())
+	assert.Empty(t, w.Poll())
+
+	require.Nil(t, os.WriteFile(b, []byte("b"), 0o644))
+	changed := w.Poll()
+	assert.Contains(t, changed, b)
+
+	require.Nil(t, os.Remove(a))
+	assert.Contains(t, w.Poll(), a)
+}
+
+func TestWatcher_Watch(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.goat")
+	b := filepath.Join(dir, "b.goat")
+
+	require.Nil(t, os.WriteFile(a, []byte("a"), 0o644))
+	require.Nil(t, os.WriteFile(b, []byte("b"), 0o644))
+
+	w := New(5*time.Millisecond, 50*time.Millisecond)
+	w.Set([]string{a, b})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cChanged := w.Watch(ctx)
+
+	require.Nil(t, os.WriteFile(a, []byte("aa"), 0o644))
+	time.Sleep(20 * time.Millisecond)
+	require.Nil(t, os.WriteFile(b, []byte("bb"), 0o644))
+
+	select {
+	case changed := <-cChanged:
+		assert.Equal(t, []string{a, b}, changed)
+	case <-time.After(time.Second):
+		t.Fatal("no changes reported")
+	}
+
+	cancel()
+	_, ok := <-cChanged
+	assert.False(t, ok)
+}